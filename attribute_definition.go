@@ -0,0 +1,231 @@
+package zcl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+)
+
+var (
+	ErrAttributeNotWritable       = errors.New("attribute is not writable")
+	ErrAttributeNotReportable     = errors.New("attribute is not reportable")
+	ErrAttributeDataTypeMismatch  = errors.New("data type does not match attribute definition")
+	ErrAttributeDefinitionInvalid = errors.New("attribute definition has no data type")
+)
+
+type AttributeAccess uint8
+
+const (
+	AccessRead AttributeAccess = 1 << iota
+	AccessWrite
+	AccessReport
+	AccessScene
+
+	AccessReadWrite  = AccessRead | AccessWrite
+	AccessReadReport = AccessRead | AccessReport
+)
+
+/*
+ * Minimum, Maximum and Default are optional and may be provided as any Go type accepted by NewAttributeDataTypeValue
+ * for the DataType, Minimum and Maximum are only enforced for numeric data types.
+ */
+
+type AttributeDefinition struct {
+	ID        AttributeID
+	Name      string
+	DataType  AttributeDataType
+	Access    AttributeAccess
+	Minimum   interface{}
+	Maximum   interface{}
+	Default   interface{}
+	Mandatory bool
+}
+
+func (d AttributeDefinition) Readable() bool {
+	return d.Access&AccessRead == AccessRead
+}
+
+func (d AttributeDefinition) Writable() bool {
+	return d.Access&AccessWrite == AccessWrite
+}
+
+func (d AttributeDefinition) Reportable() bool {
+	return d.Access&AccessReport == AccessReport
+}
+
+// Value constructs an AttributeDataTypeValue of the attributes data type holding v, returning an error if v is not
+// compatible with the data type or is outside of the attributes range.
+func (d AttributeDefinition) Value(v interface{}) (AttributeDataTypeValue, error) {
+	if d.DataType == TypeNull {
+		return AttributeDataTypeValue{}, fmt.Errorf("attribute %s: %w", d.Name, ErrAttributeDefinitionInvalid)
+	}
+
+	value, err := NewAttributeDataTypeValue(d.DataType, v)
+
+	if err != nil {
+		return AttributeDataTypeValue{}, fmt.Errorf("attribute %s: %w", d.Name, err)
+	}
+
+	if err := d.checkRange(value.Value); err != nil {
+		return AttributeDataTypeValue{}, err
+	}
+
+	return value, nil
+}
+
+// DefaultValue returns the attributes default as an AttributeDataTypeValue, if the attribute has no default the zero
+// value of the data type is returned.
+func (d AttributeDefinition) DefaultValue() (AttributeDataTypeValue, error) {
+	if d.Default == nil {
+		return d.Value(zeroValue(d.DataType))
+	}
+
+	return d.Value(d.Default)
+}
+
+// ReportableChange converts v to the Go type used for the reportable change of the attribute when configuring
+// reporting, discrete data types do not have a reportable change and nil is returned.
+func (d AttributeDefinition) ReportableChange(v interface{}) (interface{}, error) {
+	if !d.Reportable() {
+		return nil, fmt.Errorf("attribute %s: %w", d.Name, ErrAttributeNotReportable)
+	}
+
+	if DiscreteTypes[d.DataType] {
+		return nil, nil
+	}
+
+	change, err := canonicalValue(d.DataType, v)
+
+	if err != nil {
+		return nil, fmt.Errorf("attribute %s: %w", d.Name, err)
+	}
+
+	return change, nil
+}
+
+// ValidateReporting checks that reporting may be configured for the attribute with the data type and reportable change,
+// the attribute must be reportable and the data type must be the attributes. The reportable change is returned as
+// its canonical Go type, or nil for discrete types.
+func (d AttributeDefinition) ValidateReporting(dataType AttributeDataType, reportableChange interface{}) (interface{}, error) {
+	if dataType != d.DataType {
+		return nil, fmt.Errorf("attribute %s: %w: %s provided, %s expected", d.Name, ErrAttributeDataTypeMismatch, dataType, d.DataType)
+	}
+
+	return d.ReportableChange(reportableChange)
+}
+
+// ValidateWrite checks that value may be written to the attribute, the attribute must be writable and the value must
+// be of the attributes data type and within its range.
+func (d AttributeDefinition) ValidateWrite(value AttributeDataTypeValue) error {
+	if !d.Writable() {
+		return fmt.Errorf("attribute %s: %w", d.Name, ErrAttributeNotWritable)
+	}
+
+	if value.DataType != d.DataType {
+		return fmt.Errorf("attribute %s: %w: %s provided, %s expected", d.Name, ErrAttributeDataTypeMismatch, value.DataType, d.DataType)
+	}
+
+	canonical, err := canonicalValue(d.DataType, value.Value)
+
+	if err != nil {
+		return fmt.Errorf("attribute %s: %w", d.Name, err)
+	}
+
+	return d.checkRange(canonical)
+}
+
+func (d AttributeDefinition) checkRange(v interface{}) error {
+	if _, isNonValue := v.(NonValue); isNonValue {
+		return nil
+	}
+
+	if d.Minimum != nil {
+		if cmp, comparable := compareValues(d.DataType, v, d.Minimum); comparable && cmp < 0 {
+			return fmt.Errorf("attribute %s: %w: %v is below minimum %v", d.Name, ErrValueOutOfRange, v, d.Minimum)
+		}
+	}
+
+	if d.Maximum != nil {
+		if cmp, comparable := compareValues(d.DataType, v, d.Maximum); comparable && cmp > 0 {
+			return fmt.Errorf("attribute %s: %w: %v is above maximum %v", d.Name, ErrValueOutOfRange, v, d.Maximum)
+		}
+	}
+
+	return nil
+}
+
+func compareValues(dt AttributeDataType, a interface{}, b interface{}) (int, bool) {
+	if _, isInteger := integerBitSize(dt); isInteger && !isDataType(dt) {
+		if isSignedType(dt) {
+			av, aErr := toInt64(dt, a)
+			bv, bErr := toInt64(dt, b)
+
+			if aErr != nil || bErr != nil {
+				return 0, false
+			}
+
+			return compareOrdered(av < bv, av > bv), true
+		}
+
+		av, aErr := toUint64(dt, a)
+		bv, bErr := toUint64(dt, b)
+
+		if aErr != nil || bErr != nil {
+			return 0, false
+		}
+
+		return compareOrdered(av < bv, av > bv), true
+	}
+
+	switch dt {
+	case TypeFloatSemi, TypeFloatSingle, TypeFloatDouble:
+		av, aErr := toFloat64(dt, a)
+		bv, bErr := toFloat64(dt, b)
+
+		if aErr != nil || bErr != nil {
+			return 0, false
+		}
+
+		return compareOrdered(av < bv, av > bv), true
+	}
+
+	return 0, false
+}
+
+func compareOrdered(less bool, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func zeroValue(dt AttributeDataType) interface{} {
+	switch {
+	case dt == TypeBoolean:
+		return false
+	case dt == TypeFloatSemi, dt == TypeFloatSingle, dt == TypeFloatDouble:
+		return 0.0
+	case dt == TypeStringOctet8, dt == TypeStringOctet16, dt == TypeStringCharacter8, dt == TypeStringCharacter16:
+		return ""
+	case dt == TypeTimeOfDay:
+		return TimeOfDay{}
+	case dt == TypeDate:
+		return Date{}
+	case dt == TypeSecurityKey128:
+		return zigbee.NetworkKey{}
+	case dt == TypeStructure:
+		return []AttributeDataTypeValue{}
+	case dt == TypeArray, dt == TypeSet, dt == TypeBag:
+		return AttributeSlice{DataType: TypeNull}
+	}
+
+	if _, isInteger := integerBitSize(dt); isInteger {
+		return 0
+	}
+
+	return nil
+}
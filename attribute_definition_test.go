@@ -0,0 +1,106 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_AttributeDefinition(t *testing.T) {
+	percentage := AttributeDefinition{ID: 0x0021, Name: "BatteryPercentageRemaining", DataType: TypeUnsignedInt8, Access: AccessReadReport, Maximum: 200, Default: 0}
+	temperature := AttributeDefinition{ID: 0x0000, Name: "MeasuredValue", DataType: TypeSignedInt16, Access: AccessReadReport, Minimum: -27315}
+	onTime := AttributeDefinition{ID: 0x4001, Name: "OnTime", DataType: TypeUnsignedInt16, Access: AccessReadWrite}
+	onOff := AttributeDefinition{ID: 0x0000, Name: "OnOff", DataType: TypeBoolean, Access: AccessReadReport | AccessScene}
+
+	t.Run("access flags are reported", func(t *testing.T) {
+		assert.True(t, percentage.Readable())
+		assert.False(t, percentage.Writable())
+		assert.True(t, percentage.Reportable())
+
+		assert.True(t, onTime.Writable())
+		assert.False(t, onTime.Reportable())
+	})
+
+	t.Run("values are constructed with the attributes data type", func(t *testing.T) {
+		value, err := percentage.Value(100)
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint64(100)}, value)
+
+		value, err = temperature.Value(-100)
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeDataTypeValue{DataType: TypeSignedInt16, Value: int64(-100)}, value)
+	})
+
+	t.Run("values outside of the attributes range are rejected", func(t *testing.T) {
+		_, err := percentage.Value(201)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = percentage.Value(256)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = temperature.Value(-27316)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = temperature.Value(NonValue{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("default values are provided, falling back to the zero value", func(t *testing.T) {
+		value, err := percentage.DefaultValue()
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint64(0)}, value)
+
+		value, err = onOff.DefaultValue()
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeDataTypeValue{DataType: TypeBoolean, Value: false}, value)
+	})
+
+	t.Run("reportable changes are converted for analog types and omitted for discrete types", func(t *testing.T) {
+		change, err := temperature.ReportableChange(50)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(50), change)
+
+		change, err = onOff.ReportableChange(1)
+		assert.NoError(t, err)
+		assert.Nil(t, change)
+
+		_, err = onTime.ReportableChange(1)
+		assert.True(t, errors.Is(err, ErrAttributeNotReportable))
+
+		_, err = percentage.ReportableChange(-1)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("reporting is validated for access, data type and reportable change", func(t *testing.T) {
+		change, err := temperature.ValidateReporting(TypeSignedInt16, 50)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(50), change)
+
+		_, err = temperature.ValidateReporting(TypeUnsignedInt16, 50)
+		assert.True(t, errors.Is(err, ErrAttributeDataTypeMismatch))
+
+		_, err = onTime.ValidateReporting(TypeUnsignedInt16, 1)
+		assert.True(t, errors.Is(err, ErrAttributeNotReportable))
+
+		_, err = percentage.ValidateReporting(TypeUnsignedInt8, 0x100)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("writes are validated for access, data type and range", func(t *testing.T) {
+		assert.NoError(t, onTime.ValidateWrite(AttributeDataTypeValue{DataType: TypeUnsignedInt16, Value: uint16(300)}))
+
+		err := percentage.ValidateWrite(AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint8(1)})
+		assert.True(t, errors.Is(err, ErrAttributeNotWritable))
+
+		err = onTime.ValidateWrite(AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint8(1)})
+		assert.True(t, errors.Is(err, ErrAttributeDataTypeMismatch))
+
+		err = onTime.ValidateWrite(AttributeDataTypeValue{DataType: TypeUnsignedInt16, Value: 0x10000})
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		limited := onTime
+		limited.Minimum = 10
+		err = limited.ValidateWrite(AttributeDataTypeValue{DataType: TypeUnsignedInt16, Value: uint16(9)})
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+}
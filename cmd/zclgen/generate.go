@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+const header = `// Code generated by zclgen from {{.Source}}. DO NOT EDIT.
+
+`
+
+var clusterTemplate = template.Must(template.New("cluster").Parse(header + `package {{.Package}}
+
+{{if eq (len .Imports) 1}}import "{{index .Imports 0}}"
+{{else}}import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{end}}
+{{- if .Attributes}}
+const (
+{{- range .Attributes}}
+	{{.Name}} = zcl.AttributeID({{printf "0x%04x" .Code}})
+{{- end}}
+)
+{{end}}
+{{- if .Commands}}
+const (
+{{- range .Commands}}
+{{- if .StartsGroup}}
+{{end}}
+	{{.IdentifierName}} = zcl.CommandIdentifier({{printf "0x%02x" .Code}})
+{{- end}}
+)
+{{- range .Commands}}
+
+type {{.Name}} struct
+{{- if .Fields}} {
+{{- range .Fields}}
+	{{.Name}} {{.GoType}} {{.Tag}}
+{{- end}}
+}
+{{- else}}{}
+{{- end}}
+{{- end}}
+{{end}}
+`))
+
+var attributesTemplate = template.Must(template.New("attributes").Parse(header + `package {{.Package}}
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+{{- range .Attributes}}
+	{{.Definition}},
+{{- end}}
+}
+`))
+
+var registerTemplate = template.Must(template.New("register").Parse(header + `package {{.Package}}
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.{{.ClusterConstant}}, zigbee.NoManufacturer, Attributes...)
+{{- range $i, $command := .Commands}}
+{{- if or (eq $i 0) .StartsGroup}}
+{{end}}
+	cr.MustRegisterLocal(zcl.{{$.ClusterConstant}}, zigbee.NoManufacturer, {{.Direction}}, {{.IdentifierName}}, &{{.Name}}{})
+{{- end}}
+{{- range $i, $command := .Responses}}
+{{- if eq $i 0}}
+{{end}}
+	cr.MustRegisterLocalResponse(zcl.{{$.ClusterConstant}}, zigbee.NoManufacturer, {{.Direction}}, {{.IdentifierName}}, {{.ResponseIdentifierName}})
+{{- end}}
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(header + `package {{.Package}}
+
+import (
+{{- if .Commands}}
+	"github.com/shimmeringbee/bytecodec"
+{{- end}}
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+{{- if .Attributes}}
+
+func Test_Attributes(t *testing.T) {
+	t.Run("the attributes are registered in the command registry", func(t *testing.T) {
+		cr := zcl.NewCommandRegistry()
+		Register(cr)
+
+		assert.Equal(t, Attributes, cr.Attributes(zcl.{{.ClusterConstant}}, zigbee.NoManufacturer))
+	})
+}
+{{- end}}
+{{- range .Commands}}
+
+func Test_{{.Name}}(t *testing.T) {
+	t.Run("marshals and unmarshalls correctly", func(t *testing.T) {
+{{- if .Signed}}
+		t.Skip("bytecodec does not currently support signed ints, see issue #13.")
+{{- end}}
+		expectedCommand := {{.Name}}{
+{{- range .Fields}}
+			{{.Name}}: {{.Value}},
+{{- end}}
+		}
+		actualCommand := {{.Name}}{}
+		expectedBytes := {{.ByteLiteral}}
+
+		actualBytes, err := bytecodec.Marshal(&expectedCommand)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBytes, actualBytes)
+
+		err = bytecodec.Unmarshal(expectedBytes, &actualCommand)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCommand, actualCommand)
+	})
+
+	t.Run("the message is registered in the command registry", func(t *testing.T) {
+		cr := zcl.NewCommandRegistry()
+		Register(cr)
+
+		id, err := cr.GetLocalCommandIdentifier(zcl.{{$.ClusterConstant}}, zigbee.NoManufacturer, {{.Direction}}, &{{.Name}}{})
+		assert.NoError(t, err)
+		assert.Equal(t, {{.IdentifierName}}, id)
+	})
+}
+{{- end}}
+`))
+
+type generatedFile struct {
+	Name     string
+	Contents []byte
+}
+
+// generate renders the files of a cluster package, the output is formatted with gofmt.
+func generate(c cluster) ([]generatedFile, error) {
+	files := []struct {
+		name     string
+		template *template.Template
+	}{
+		{c.Package + ".go", clusterTemplate},
+		{"attributes.go", attributesTemplate},
+		{"register.go", registerTemplate},
+		{c.Package + "_test.go", testTemplate},
+	}
+
+	var generated []generatedFile
+
+	for _, file := range files {
+		buffer := &bytes.Buffer{}
+
+		if err := file.template.Execute(buffer, c); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.name, err)
+		}
+
+		contents, err := format.Source(buffer.Bytes())
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.name, err)
+		}
+
+		generated = append(generated, generatedFile{Name: file.name, Contents: contents})
+	}
+
+	return generated, nil
+}
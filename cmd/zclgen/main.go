@@ -0,0 +1,182 @@
+// Command zclgen generates a cluster package, in the style of those in commands/local, from the ZCL XML cluster
+// definitions used by ZAP. It is intended to be run by go generate from within the package directory:
+//
+//	//go:generate go run github.com/shimmeringbee/zcl/cmd/zclgen -xml ../../../cmd/zclgen/testdata/occupancy-sensing.xml
+//
+// Attribute constants, command structs, the attribute definition table, a Register function and round trip tests are
+// written to the output directory, overwriting any existing generated files.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	xmlFile := flag.String("xml", "", "ZAP XML file containing the cluster definition")
+	clusterCode := flag.String("cluster", "", "code of the cluster to generate, required if the XML contains more than one")
+	pkg := flag.String("package", "", "name of the generated package, defaults to the cluster name in snake case")
+	out := flag.String("out", ".", "directory to write the generated package to")
+	list := flag.String("list", "", "path to list.go of the zcl package, defaults to the root of the module enclosing the working directory")
+	flag.Parse()
+
+	if err := run(*xmlFile, *clusterCode, *pkg, *out, *list); err != nil {
+		fmt.Fprintf(os.Stderr, "zclgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(xmlFile string, clusterCode string, pkg string, out string, list string) error {
+	if xmlFile == "" {
+		return errors.New("-xml must be provided")
+	}
+
+	if list == "" {
+		root, err := moduleRoot(".")
+
+		if err != nil {
+			return err
+		}
+
+		list = filepath.Join(root, "list.go")
+	}
+
+	clusterConstants, err := readClusterConstants(list)
+
+	if err != nil {
+		return err
+	}
+
+	config, err := readDefinitions(xmlFile)
+
+	if err != nil {
+		return err
+	}
+
+	zc, err := selectCluster(config, clusterCode)
+
+	if err != nil {
+		return err
+	}
+
+	c, err := buildCluster(config, zc, clusterConstants, filepath.Base(xmlFile), pkg)
+
+	if err != nil {
+		return err
+	}
+
+	files, err := generate(c)
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(out, file.Name), file.Contents, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func selectCluster(config zapConfigurator, clusterCode string) (zapCluster, error) {
+	if clusterCode == "" {
+		if len(config.Clusters) != 1 {
+			return zapCluster{}, fmt.Errorf("-cluster must be provided, XML contains %d clusters", len(config.Clusters))
+		}
+
+		return config.Clusters[0], nil
+	}
+
+	code, err := parseCode(clusterCode, 16)
+
+	if err != nil {
+		return zapCluster{}, fmt.Errorf("invalid -cluster: %w", err)
+	}
+
+	for _, zc := range config.Clusters {
+		if other, err := parseCode(zc.Code, 16); err == nil && other == code {
+			return zc, nil
+		}
+	}
+
+	return zapCluster{}, fmt.Errorf("cluster 0x%04x not found in XML", code)
+}
+
+func moduleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+
+		if parent == dir {
+			return "", errors.New("unable to find go.mod, provide -list")
+		}
+
+		dir = parent
+	}
+}
+
+// readClusterConstants parses list.go for the names of the cluster ID constants, as they can not be derived from the
+// cluster names reliably.
+func readClusterConstants(filename string) (map[uint16]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	constants := map[uint16]string{}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+
+			if len(valueSpec.Names) != 1 || len(valueSpec.Values) != 1 {
+				continue
+			}
+
+			call, ok := valueSpec.Values[0].(*ast.CallExpr)
+
+			if !ok || len(call.Args) != 1 || !strings.HasSuffix(valueSpec.Names[0].Name, "Id") {
+				continue
+			}
+
+			literal, ok := call.Args[0].(*ast.BasicLit)
+
+			if !ok {
+				continue
+			}
+
+			if code, err := strconv.ParseUint(literal.Value, 0, 16); err == nil {
+				constants[uint16(code)] = valueSpec.Names[0].Name
+			}
+		}
+	}
+
+	return constants, nil
+}
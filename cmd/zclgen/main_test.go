@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func Test_Generate(t *testing.T) {
+	t.Run("generated identify package matches golden files", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "zclgen")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		err = run(filepath.Join("testdata", "identify.xml"), "", "", dir, filepath.Join("..", "..", "list.go"))
+		assert.NoError(t, err)
+
+		files, err := ioutil.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, files, 4)
+
+		for _, file := range files {
+			actual, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+			assert.NoError(t, err)
+
+			golden := filepath.Join("testdata", "golden", "identify", file.Name()+".golden")
+
+			if *update {
+				assert.NoError(t, ioutil.WriteFile(golden, actual, 0644))
+			}
+
+			expected, err := ioutil.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual), file.Name())
+		}
+	})
+
+	t.Run("a cluster must be selected if the XML contains more than one", func(t *testing.T) {
+		_, err := selectCluster(zapConfigurator{Clusters: []zapCluster{{Code: "0x0003"}, {Code: "0x0006"}}}, "")
+		assert.Error(t, err)
+
+		zc, err := selectCluster(zapConfigurator{Clusters: []zapCluster{{Code: "0x0003"}, {Code: "0x0006"}}}, "6")
+		assert.NoError(t, err)
+		assert.Equal(t, "0x0006", zc.Code)
+	})
+
+	t.Run("cluster constants are read from list.go", func(t *testing.T) {
+		constants, err := readClusterConstants(filepath.Join("..", "..", "list.go"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "OnOffId", constants[0x0006])
+		assert.Equal(t, "AirConcentrationOzoneId", constants[0x0415])
+	})
+}
+
+func Test_goName(t *testing.T) {
+	t.Run("converts ZAP names to exported Go names", func(t *testing.T) {
+		assert.Equal(t, "IdentifyTime", goName("identify time"))
+		assert.Equal(t, "IdentifyTime", goName("IDENTIFY_TIME"))
+		assert.Equal(t, "IdentifyTime", goName("identifyTime"))
+		assert.Equal(t, "PIROccupiedToUnoccupiedDelay", goName("PIR occupied to unoccupied delay"))
+	})
+
+	t.Run("converts cluster names to package names", func(t *testing.T) {
+		assert.Equal(t, "occupancy_sensing", packageName("Occupancy Sensing"))
+		assert.Equal(t, "onoff", packageName("OnOff"))
+		assert.Equal(t, "on_off", packageName("On/Off"))
+	})
+}
+
+func Test_buildAttribute(t *testing.T) {
+	t.Run("access, range, default and mandatory are converted", func(t *testing.T) {
+		a, err := buildAttribute(zapAttribute{Code: "0x0000", Type: "INT16S", Min: "0x954D", Max: "0x7FFE", Default: "0x8000", Reportable: "true", Optional: "false", Text: "measured value"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "MeasuredValue", a.Name)
+		assert.Equal(t, `{ID: MeasuredValue, Name: "MeasuredValue", DataType: zcl.TypeSignedInt16, Access: zcl.AccessReadReport, Minimum: -27315, Maximum: 32766, Default: zcl.NonValue{}, Mandatory: true}`, a.Definition)
+
+		a, err = buildAttribute(zapAttribute{Code: "0x0001", Type: "BOOLEAN", Default: "1", Writable: "true", Reportable: "true", Text: "flag"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, `{ID: Flag, Name: "Flag", DataType: zcl.TypeBoolean, Access: zcl.AccessReadWrite | zcl.AccessReport, Default: true}`, a.Definition)
+	})
+
+	t.Run("named enum types resolve to their underlying type", func(t *testing.T) {
+		a, err := buildAttribute(zapAttribute{Code: "0x0001", Type: "ZoneType", Access: []zapAccess{{Op: "read"}, {Op: "write"}}, Text: "zone type"}, map[string]string{"ZONETYPE": "ENUM16"})
+		assert.NoError(t, err)
+		assert.Equal(t, `{ID: ZoneType, Name: "ZoneType", DataType: zcl.TypeEnum16, Access: zcl.AccessReadWrite}`, a.Definition)
+	})
+
+	t.Run("unsupported types and manufacturer specific attributes are rejected", func(t *testing.T) {
+		_, err := buildAttribute(zapAttribute{Code: "0x0000", Type: "SomeStruct", Text: "thing"}, nil)
+		assert.Error(t, err)
+
+		_, err = buildAttribute(zapAttribute{Code: "0x0000", Type: "INT8U", ManufacturerCode: "0x1234", Text: "thing"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func Test_buildCommand(t *testing.T) {
+	t.Run("fields are tagged and given unique sample values", func(t *testing.T) {
+		imports := map[string]bool{}
+
+		cmd, err := buildCommand(zapCommand{Source: "client", Code: "0x01", Name: "Everything", Args: []zapArg{
+			{Name: "a24", Type: "INT24U"},
+			{Name: "flag", Type: "BOOLEAN"},
+			{Name: "label", Type: "LONG_CHAR_STRING"},
+			{Name: "ieee", Type: "IEEE_ADDRESS"},
+			{Name: "list", Type: "INT16U", Array: "true"},
+		}}, nil, imports)
+		assert.NoError(t, err)
+		assert.True(t, imports[zigbeeImport])
+		assert.False(t, cmd.Signed)
+
+		assert.Equal(t, []field{
+			{Name: "A24", GoType: "uint32", Tag: "`bcfieldwidth:\"24\"`", Value: "0x112233", Bytes: []byte{0x33, 0x22, 0x11}},
+			{Name: "Flag", GoType: "bool", Value: "true", Bytes: []byte{0x01}},
+			{Name: "Label", GoType: "string", Tag: "`bcstringtype:\"prefix,16\"`", Value: `"zcl"`, Bytes: []byte{0x03, 0x00, 'z', 'c', 'l'}},
+			{Name: "Ieee", GoType: "zigbee.IEEEAddress", Value: "zigbee.IEEEAddress(0x445566778899aabb)", Bytes: []byte{0xbb, 0xaa, 0x99, 0x88, 0x77, 0x66, 0x55, 0x44}},
+			{Name: "List", GoType: "[]uint16", Value: "[]uint16{0xccdd}", Bytes: []byte{0xdd, 0xcc}},
+		}, cmd.Fields)
+	})
+
+	t.Run("commands with signed fields are marked", func(t *testing.T) {
+		cmd, err := buildCommand(zapCommand{Source: "server", Code: "0x02", Name: "signed thing", Args: []zapArg{{Name: "value", Type: "INT16S"}}}, nil, map[string]bool{})
+		assert.NoError(t, err)
+		assert.Equal(t, "SignedThing", cmd.Name)
+		assert.Equal(t, "zcl.ServerToClient", cmd.Direction)
+		assert.True(t, cmd.Signed)
+	})
+
+	t.Run("arrays which are not the final argument are rejected", func(t *testing.T) {
+		_, err := buildCommand(zapCommand{Source: "client", Code: "0x01", Name: "Bad", Args: []zapArg{{Name: "list", Type: "INT8U", Array: "true"}, {Name: "after", Type: "INT8U"}}}, nil, map[string]bool{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,547 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	zclImport    = "github.com/shimmeringbee/zcl"
+	zigbeeImport = "github.com/shimmeringbee/zigbee"
+)
+
+type cluster struct {
+	Name            string
+	Package         string
+	Source          string
+	ClusterConstant string
+	Attributes      []attribute
+	Commands        []command
+	Imports         []string
+}
+
+type attribute struct {
+	Name       string
+	Code       uint16
+	Definition string
+}
+
+type command struct {
+	Name      string
+	Code      uint8
+	Direction string
+	Fields    []field
+	Signed    bool
+
+	// Response is the name of the command's specific response, if it has one.
+	Response string
+
+	// StartsGroup is set on the first server to client command when there are also client to server commands.
+	StartsGroup bool
+}
+
+type field struct {
+	Name   string
+	GoType string
+	Tag    string
+	Value  string
+	Bytes  []byte
+}
+
+func (c cluster) command(name string) (command, bool) {
+	for _, cmd := range c.Commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// Responses returns the commands which have a specific response.
+func (c cluster) Responses() []command {
+	var responses []command
+
+	for _, cmd := range c.Commands {
+		if cmd.Response != "" {
+			responses = append(responses, cmd)
+		}
+	}
+
+	return responses
+}
+
+func (c command) ResponseIdentifierName() string {
+	return c.Response + "Id"
+}
+
+func (c command) IdentifierName() string {
+	return c.Name + "Id"
+}
+
+func (c command) ClientToServer() bool {
+	return c.Direction == "zcl.ClientToServer"
+}
+
+func (c command) ByteLiteral() string {
+	var bytes []byte
+
+	for _, f := range c.Fields {
+		bytes = append(bytes, f.Bytes...)
+	}
+
+	if len(bytes) == 0 {
+		return "[]byte(nil)"
+	}
+
+	var parts []string
+
+	for _, b := range bytes {
+		parts = append(parts, fmt.Sprintf("0x%02x", b))
+	}
+
+	return "[]byte{" + strings.Join(parts, ", ") + "}"
+}
+
+// goName converts a ZAP name such as "identify time", "IDENTIFY_TIME" or "identifyTime" into an exported Go name.
+func goName(name string) string {
+	if strings.ToUpper(name) == name {
+		name = strings.ToLower(name)
+	}
+
+	var out []rune
+	startOfWord := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			startOfWord = true
+			continue
+		}
+
+		if startOfWord {
+			r = unicode.ToUpper(r)
+		}
+
+		out = append(out, r)
+		startOfWord = false
+	}
+
+	return string(out)
+}
+
+// packageName converts a cluster name such as "Occupancy Sensing" into a package name such as "occupancy_sensing".
+func packageName(name string) string {
+	var words []string
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words = append(words, word)
+	}
+
+	return strings.Join(words, "_")
+}
+
+func parseCode(code string, bits int) (uint64, error) {
+	return strconv.ParseUint(strings.TrimSpace(code), 0, bits)
+}
+
+func buildCluster(config zapConfigurator, zc zapCluster, clusterConstants map[uint16]string, source string, pkg string) (cluster, error) {
+	code, err := parseCode(zc.Code, 16)
+
+	if err != nil {
+		return cluster{}, fmt.Errorf("cluster %s: invalid code: %w", zc.Name, err)
+	}
+
+	if zc.ManufacturerCode != "" {
+		return cluster{}, fmt.Errorf("cluster %s: manufacturer specific clusters are not supported", zc.Name)
+	}
+
+	clusterConstant, found := clusterConstants[uint16(code)]
+
+	if !found {
+		return cluster{}, fmt.Errorf("cluster %s: 0x%04x is not declared in list.go", zc.Name, code)
+	}
+
+	if pkg == "" {
+		pkg = packageName(zc.Name)
+	}
+
+	c := cluster{
+		Name:            zc.Name,
+		Package:         pkg,
+		Source:          source,
+		ClusterConstant: clusterConstant,
+	}
+
+	namedTypes := map[string]string{}
+
+	for _, decl := range append(config.Enums, config.Bitmaps...) {
+		namedTypes[strings.ToUpper(decl.Name)] = strings.ToUpper(decl.Type)
+	}
+
+	imports := map[string]bool{zclImport: true}
+	names := map[string]string{}
+
+	claim := func(name string, what string) error {
+		if other, found := names[name]; found {
+			return fmt.Errorf("cluster %s: %s and %s would both be named %s", zc.Name, what, other, name)
+		}
+
+		names[name] = what
+		return nil
+	}
+
+	for _, za := range zc.Attributes {
+		if za.Side != "" && za.Side != "server" {
+			continue
+		}
+
+		a, err := buildAttribute(za, namedTypes)
+
+		if err != nil {
+			return cluster{}, fmt.Errorf("cluster %s: %w", zc.Name, err)
+		}
+
+		if err := claim(a.Name, "attribute "+a.Name); err != nil {
+			return cluster{}, err
+		}
+
+		c.Attributes = append(c.Attributes, a)
+	}
+
+	for _, zcmd := range zc.Commands {
+		cmd, err := buildCommand(zcmd, namedTypes, imports)
+
+		if err != nil {
+			return cluster{}, fmt.Errorf("cluster %s: %w", zc.Name, err)
+		}
+
+		if err := claim(cmd.Name, "command "+cmd.Name); err != nil {
+			return cluster{}, err
+		}
+
+		if err := claim(cmd.IdentifierName(), "command identifier "+cmd.IdentifierName()); err != nil {
+			return cluster{}, err
+		}
+
+		c.Commands = append(c.Commands, cmd)
+	}
+
+	if len(c.Attributes) == 0 && len(c.Commands) == 0 {
+		return cluster{}, fmt.Errorf("cluster %s: has no server attributes or commands", zc.Name)
+	}
+
+	sort.SliceStable(c.Attributes, func(i, j int) bool { return c.Attributes[i].Code < c.Attributes[j].Code })
+	sort.SliceStable(c.Commands, func(i, j int) bool {
+		if c.Commands[i].ClientToServer() != c.Commands[j].ClientToServer() {
+			return c.Commands[i].ClientToServer()
+		}
+
+		return c.Commands[i].Code < c.Commands[j].Code
+	})
+
+	for i := 1; i < len(c.Commands); i++ {
+		c.Commands[i].StartsGroup = c.Commands[i].Direction != c.Commands[i-1].Direction
+	}
+
+	for _, cmd := range c.Commands {
+		if cmd.Response == "" {
+			continue
+		}
+
+		response, found := c.command(cmd.Response)
+
+		if !found {
+			return cluster{}, fmt.Errorf("cluster %s: command %s: unknown response %s", zc.Name, cmd.Name, cmd.Response)
+		}
+
+		if response.Direction == cmd.Direction {
+			return cluster{}, fmt.Errorf("cluster %s: command %s: response %s is not in the opposite direction", zc.Name, cmd.Name, cmd.Response)
+		}
+	}
+
+	for imp := range imports {
+		c.Imports = append(c.Imports, imp)
+	}
+
+	sort.Strings(c.Imports)
+
+	return c, nil
+}
+
+func resolveType(name string, namedTypes map[string]string) (zclType, bool) {
+	name = strings.ToUpper(name)
+
+	if underlying, found := namedTypes[name]; found {
+		name = underlying
+	}
+
+	t, found := zapTypes[name]
+	return t, found
+}
+
+func buildAttribute(za zapAttribute, namedTypes map[string]string) (attribute, error) {
+	code, err := parseCode(za.Code, 16)
+
+	if err != nil {
+		return attribute{}, fmt.Errorf("attribute %s: invalid code: %w", za.Define, err)
+	}
+
+	if za.ManufacturerCode != "" {
+		return attribute{}, fmt.Errorf("attribute 0x%04x: manufacturer specific attributes are not supported", code)
+	}
+
+	name := strings.TrimSpace(za.Description)
+	if name == "" {
+		name = strings.TrimSpace(za.Text)
+	}
+	if name == "" {
+		name = za.Define
+	}
+
+	a := attribute{Name: goName(name), Code: uint16(code)}
+
+	t, found := resolveType(za.Type, namedTypes)
+
+	if !found {
+		return attribute{}, fmt.Errorf("attribute %s: unsupported type %s", a.Name, za.Type)
+	}
+
+	writable := za.Writable == "true"
+	for _, access := range za.Access {
+		if access.Op == "write" {
+			writable = true
+		}
+	}
+
+	reportable := za.Reportable == "true"
+
+	var access string
+
+	switch {
+	case writable && reportable:
+		access = "zcl.AccessReadWrite | zcl.AccessReport"
+	case writable:
+		access = "zcl.AccessReadWrite"
+	case reportable:
+		access = "zcl.AccessReadReport"
+	default:
+		access = "zcl.AccessRead"
+	}
+
+	parts := []string{
+		"ID: " + a.Name,
+		"Name: " + strconv.Quote(a.Name),
+		"DataType: zcl." + t.DataType,
+		"Access: " + access,
+	}
+
+	for _, limit := range []struct {
+		field string
+		value string
+	}{{"Minimum", za.Min}, {"Maximum", za.Max}, {"Default", za.Default}} {
+		if literal, ok := valueLiteral(t, limit.value, limit.field == "Default"); ok {
+			parts = append(parts, limit.field+": "+literal)
+		}
+	}
+
+	if za.Optional == "false" {
+		parts = append(parts, "Mandatory: true")
+	}
+
+	a.Definition = "{" + strings.Join(parts, ", ") + "}"
+
+	return a, nil
+}
+
+// valueLiteral converts a ZAP min, max or default to a Go literal for the attribute type, values which can not be
+// represented are omitted.
+func valueLiteral(t zclType, value string, isDefault bool) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return "", false
+	}
+
+	switch t.Kind {
+	case kindUnsigned:
+		v, err := parseCode(value, 64)
+		if err != nil || (t.Bits < 64 && v >= 1<<uint(t.Bits)) {
+			return "", false
+		}
+
+		if isDefault && hasNonValue(t) && v == 1<<uint(t.Bits)-1 {
+			return "zcl.NonValue{}", true
+		}
+
+		return fmt.Sprintf("0x%0*x", t.Bits/4, v), true
+	case kindSigned:
+		signed, err := strconv.ParseInt(value, 10, t.Bits)
+
+		if err != nil {
+			v, err := parseCode(value, t.Bits)
+			if err != nil {
+				return "", false
+			}
+
+			signed = int64(v)
+			if v>>uint(t.Bits-1) == 1 {
+				signed -= 1 << uint(t.Bits)
+			}
+		}
+
+		if isDefault && signed == -1<<uint(t.Bits-1) {
+			return "zcl.NonValue{}", true
+		}
+
+		return strconv.FormatInt(signed, 10), true
+	case kindBool:
+		if !isDefault {
+			return "", false
+		}
+
+		switch strings.ToLower(value) {
+		case "0", "0x00", "false":
+			return "false", true
+		case "1", "0x01", "true":
+			return "true", true
+		}
+	case kindString:
+		if isDefault {
+			return strconv.Quote(value), true
+		}
+	}
+
+	return "", false
+}
+
+func buildCommand(zc zapCommand, namedTypes map[string]string, imports map[string]bool) (command, error) {
+	code, err := parseCode(zc.Code, 8)
+
+	if err != nil {
+		return command{}, fmt.Errorf("command %s: invalid code: %w", zc.Name, err)
+	}
+
+	if zc.ManufacturerCode != "" {
+		return command{}, fmt.Errorf("command %s: manufacturer specific commands are not supported", zc.Name)
+	}
+
+	cmd := command{Name: goName(zc.Name), Code: uint8(code)}
+
+	if zc.Response != "" {
+		cmd.Response = goName(zc.Response)
+	}
+
+	switch zc.Source {
+	case "client":
+		cmd.Direction = "zcl.ClientToServer"
+	case "server":
+		cmd.Direction = "zcl.ServerToClient"
+	default:
+		return command{}, fmt.Errorf("command %s: unknown source %q", cmd.Name, zc.Source)
+	}
+
+	sample := byte(0)
+
+	for i, arg := range zc.Args {
+		t, found := resolveType(arg.Type, namedTypes)
+
+		if !found || t.GoType == "" {
+			return command{}, fmt.Errorf("command %s: argument %s: unsupported type %s", cmd.Name, arg.Name, arg.Type)
+		}
+
+		for _, imp := range t.Imports {
+			imports[imp] = true
+		}
+
+		f := field{Name: goName(arg.Name), GoType: t.GoType}
+
+		if t.Kind == kindSigned {
+			cmd.Signed = true
+		}
+
+		if (t.Kind == kindUnsigned || t.Kind == kindSigned) && goTypeBits(t.Bits) != t.Bits {
+			f.Tag = fmt.Sprintf("`bcfieldwidth:\"%d\"`", t.Bits)
+		}
+
+		if t.Kind == kindString && t.Bits == 16 {
+			f.Tag = "`bcstringtype:\"prefix,16\"`"
+		}
+
+		value, bytes := sampleValue(t, &sample)
+
+		if arg.Array == "true" {
+			if i != len(zc.Args)-1 {
+				return command{}, fmt.Errorf("command %s: argument %s: arrays are only supported as the final argument", cmd.Name, arg.Name)
+			}
+
+			if f.Tag != "" {
+				return command{}, fmt.Errorf("command %s: argument %s: arrays of %s are not supported", cmd.Name, arg.Name, arg.Type)
+			}
+
+			f.GoType = "[]" + f.GoType
+			value = f.GoType + "{" + value + "}"
+		}
+
+		f.Value = value
+		f.Bytes = bytes
+
+		cmd.Fields = append(cmd.Fields, f)
+	}
+
+	return cmd, nil
+}
+
+// sampleValue returns a Go literal and its little endian encoding for use in round trip tests, each byte of the
+// encoding is unique within a command to catch misordered fields.
+func sampleValue(t zclType, sample *byte) (string, []byte) {
+	next := func() byte {
+		*sample++
+		return 0x11 * (((*sample)-1)%15 + 1)
+	}
+
+	switch t.Kind {
+	case kindBool:
+		return "true", []byte{0x01}
+	case kindString:
+		value := "zcl"
+		bytes := []byte{byte(len(value))}
+
+		if t.Bits == 16 {
+			bytes = append(bytes, 0x00)
+		}
+
+		return strconv.Quote(value), append(bytes, []byte(value)...)
+	}
+
+	size := t.Bits / 8
+	bigEndian := make([]byte, size)
+	value := uint64(0)
+
+	for i := range bigEndian {
+		bigEndian[i] = next()
+		value = value<<8 | uint64(bigEndian[i])
+	}
+
+	if t.Kind == kindSigned {
+		bigEndian[0] &= 0x7f
+		value &^= 1 << uint(t.Bits-1)
+	}
+
+	bytes := make([]byte, size)
+	for i := range bigEndian {
+		bytes[size-1-i] = bigEndian[i]
+	}
+
+	literal := fmt.Sprintf("0x%0*x", size*2, value)
+
+	if strings.Contains(t.GoType, ".") {
+		literal = t.GoType + "(" + literal + ")"
+	}
+
+	return literal, bytes
+}
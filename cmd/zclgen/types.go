@@ -0,0 +1,99 @@
+package main
+
+import "strings"
+
+type typeKind int
+
+const (
+	kindUnsigned typeKind = iota
+	kindSigned
+	kindBool
+	kindString
+	kindOther
+)
+
+type zclType struct {
+	DataType string
+	GoType   string
+	Kind     typeKind
+	Bits     int
+	Imports  []string
+}
+
+var zapTypes = map[string]zclType{
+	"DATA8":  {DataType: "TypeData8", GoType: "uint8", Kind: kindUnsigned, Bits: 8},
+	"DATA16": {DataType: "TypeData16", GoType: "uint16", Kind: kindUnsigned, Bits: 16},
+	"DATA24": {DataType: "TypeData24", GoType: "uint32", Kind: kindUnsigned, Bits: 24},
+	"DATA32": {DataType: "TypeData32", GoType: "uint32", Kind: kindUnsigned, Bits: 32},
+
+	"BOOLEAN": {DataType: "TypeBoolean", GoType: "bool", Kind: kindBool, Bits: 8},
+
+	"BITMAP8":  {DataType: "TypeBitmap8", GoType: "uint8", Kind: kindUnsigned, Bits: 8},
+	"BITMAP16": {DataType: "TypeBitmap16", GoType: "uint16", Kind: kindUnsigned, Bits: 16},
+	"BITMAP24": {DataType: "TypeBitmap24", GoType: "uint32", Kind: kindUnsigned, Bits: 24},
+	"BITMAP32": {DataType: "TypeBitmap32", GoType: "uint32", Kind: kindUnsigned, Bits: 32},
+	"BITMAP64": {DataType: "TypeBitmap64", GoType: "uint64", Kind: kindUnsigned, Bits: 64},
+
+	"INT8U":  {DataType: "TypeUnsignedInt8", GoType: "uint8", Kind: kindUnsigned, Bits: 8},
+	"INT16U": {DataType: "TypeUnsignedInt16", GoType: "uint16", Kind: kindUnsigned, Bits: 16},
+	"INT24U": {DataType: "TypeUnsignedInt24", GoType: "uint32", Kind: kindUnsigned, Bits: 24},
+	"INT32U": {DataType: "TypeUnsignedInt32", GoType: "uint32", Kind: kindUnsigned, Bits: 32},
+	"INT40U": {DataType: "TypeUnsignedInt40", GoType: "uint64", Kind: kindUnsigned, Bits: 40},
+	"INT48U": {DataType: "TypeUnsignedInt48", GoType: "uint64", Kind: kindUnsigned, Bits: 48},
+	"INT56U": {DataType: "TypeUnsignedInt56", GoType: "uint64", Kind: kindUnsigned, Bits: 56},
+	"INT64U": {DataType: "TypeUnsignedInt64", GoType: "uint64", Kind: kindUnsigned, Bits: 64},
+
+	"INT8S":  {DataType: "TypeSignedInt8", GoType: "int8", Kind: kindSigned, Bits: 8},
+	"INT16S": {DataType: "TypeSignedInt16", GoType: "int16", Kind: kindSigned, Bits: 16},
+	"INT24S": {DataType: "TypeSignedInt24", GoType: "int32", Kind: kindSigned, Bits: 24},
+	"INT32S": {DataType: "TypeSignedInt32", GoType: "int32", Kind: kindSigned, Bits: 32},
+	"INT40S": {DataType: "TypeSignedInt40", GoType: "int64", Kind: kindSigned, Bits: 40},
+	"INT48S": {DataType: "TypeSignedInt48", GoType: "int64", Kind: kindSigned, Bits: 48},
+	"INT56S": {DataType: "TypeSignedInt56", GoType: "int64", Kind: kindSigned, Bits: 56},
+	"INT64S": {DataType: "TypeSignedInt64", GoType: "int64", Kind: kindSigned, Bits: 64},
+
+	"ENUM8":  {DataType: "TypeEnum8", GoType: "uint8", Kind: kindUnsigned, Bits: 8},
+	"ENUM16": {DataType: "TypeEnum16", GoType: "uint16", Kind: kindUnsigned, Bits: 16},
+
+	"FLOAT_SEMI":   {DataType: "TypeFloatSemi", Kind: kindOther},
+	"FLOAT_SINGLE": {DataType: "TypeFloatSingle", Kind: kindOther},
+	"SINGLE":       {DataType: "TypeFloatSingle", Kind: kindOther},
+	"FLOAT_DOUBLE": {DataType: "TypeFloatDouble", Kind: kindOther},
+	"DOUBLE":       {DataType: "TypeFloatDouble", Kind: kindOther},
+
+	"OCTET_STRING":      {DataType: "TypeStringOctet8", GoType: "string", Kind: kindString, Bits: 8},
+	"CHAR_STRING":       {DataType: "TypeStringCharacter8", GoType: "string", Kind: kindString, Bits: 8},
+	"LONG_OCTET_STRING": {DataType: "TypeStringOctet16", GoType: "string", Kind: kindString, Bits: 16},
+	"LONG_CHAR_STRING":  {DataType: "TypeStringCharacter16", GoType: "string", Kind: kindString, Bits: 16},
+
+	"TIME_OF_DAY": {DataType: "TypeTimeOfDay", Kind: kindOther},
+	"DATE":        {DataType: "TypeDate", Kind: kindOther},
+	"UTC_TIME":    {DataType: "TypeUTCTime", GoType: "zcl.UTCTime", Kind: kindUnsigned, Bits: 32},
+
+	"CLUSTER_ID":   {DataType: "TypeClusterID", GoType: "zigbee.ClusterID", Kind: kindUnsigned, Bits: 16, Imports: []string{zigbeeImport}},
+	"ATTRIBUTE_ID": {DataType: "TypeAttributeID", GoType: "zcl.AttributeID", Kind: kindUnsigned, Bits: 16},
+	"ATTRIB_ID":    {DataType: "TypeAttributeID", GoType: "zcl.AttributeID", Kind: kindUnsigned, Bits: 16},
+	"BACNET_OID":   {DataType: "TypeBACnetOID", GoType: "zcl.BACnetOID", Kind: kindUnsigned, Bits: 32},
+
+	"IEEE_ADDRESS": {DataType: "TypeIEEEAddress", GoType: "zigbee.IEEEAddress", Kind: kindUnsigned, Bits: 64, Imports: []string{zigbeeImport}},
+	"SECURITY_KEY": {DataType: "TypeSecurityKey128", Kind: kindOther},
+}
+
+// goTypeBits returns the size of the Go type used for a field, which is larger than the ZCL type for odd widths.
+func goTypeBits(bits int) int {
+	switch {
+	case bits <= 8:
+		return 8
+	case bits <= 16:
+		return 16
+	case bits <= 32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// hasNonValue returns true if the integer type reserves its maximum (or minimum if signed) value as a non-value.
+func hasNonValue(t zclType) bool {
+	return !strings.HasPrefix(t.DataType, "TypeBitmap") && !strings.HasPrefix(t.DataType, "TypeData")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+)
+
+/*
+ * Subset of the ZCL XML cluster definition format used by ZAP, as found in zap/zcl-builtin/silabs/*.xml and the
+ * connectedhomeip data model. Only the elements needed to generate cluster packages are decoded.
+ */
+
+type zapConfigurator struct {
+	Clusters []zapCluster  `xml:"cluster"`
+	Enums    []zapTypeDecl `xml:"enum"`
+	Bitmaps  []zapTypeDecl `xml:"bitmap"`
+}
+
+type zapTypeDecl struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type zapCluster struct {
+	Name             string         `xml:"name"`
+	Code             string         `xml:"code"`
+	ManufacturerCode string         `xml:"manufacturerCode,attr"`
+	Attributes       []zapAttribute `xml:"attribute"`
+	Commands         []zapCommand   `xml:"command"`
+}
+
+type zapAttribute struct {
+	Side             string      `xml:"side,attr"`
+	Code             string      `xml:"code,attr"`
+	Define           string      `xml:"define,attr"`
+	Type             string      `xml:"type,attr"`
+	Min              string      `xml:"min,attr"`
+	Max              string      `xml:"max,attr"`
+	Writable         string      `xml:"writable,attr"`
+	Reportable       string      `xml:"reportable,attr"`
+	Default          string      `xml:"default,attr"`
+	Optional         string      `xml:"optional,attr"`
+	ManufacturerCode string      `xml:"manufacturerCode,attr"`
+	Description      string      `xml:"description"`
+	Access           []zapAccess `xml:"access"`
+	Text             string      `xml:",chardata"`
+}
+
+type zapAccess struct {
+	Op string `xml:"op,attr"`
+}
+
+type zapCommand struct {
+	Source           string   `xml:"source,attr"`
+	Code             string   `xml:"code,attr"`
+	Name             string   `xml:"name,attr"`
+	ManufacturerCode string   `xml:"manufacturerCode,attr"`
+	Response         string   `xml:"response,attr"`
+	Args             []zapArg `xml:"arg"`
+}
+
+type zapArg struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Array string `xml:"array,attr"`
+}
+
+func readDefinitions(filename string) (zapConfigurator, error) {
+	data, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		return zapConfigurator{}, err
+	}
+
+	var configurator zapConfigurator
+
+	if err := xml.Unmarshal(data, &configurator); err != nil {
+		return zapConfigurator{}, err
+	}
+
+	return configurator, nil
+}
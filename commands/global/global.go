@@ -32,12 +32,18 @@ type ReadAttributes struct {
 	Identifier []zcl.AttributeID
 }
 
+// ReadAttributeResponseRecord retains a uint8 Status, bytecodec is unable to evaluate bcincludeif against named types,
+// use StatusCode for the typed value.
 type ReadAttributeResponseRecord struct {
 	Identifier    zcl.AttributeID
 	Status        uint8
 	DataTypeValue *zcl.AttributeDataTypeValue `bcincludeif:"Status==0"`
 }
 
+func (r ReadAttributeResponseRecord) StatusCode() zcl.Status {
+	return zcl.Status(r.Status)
+}
+
 type ReadAttributesResponse struct {
 	Records []ReadAttributeResponseRecord
 }
@@ -52,7 +58,7 @@ type WriteAttributesRecord struct {
 }
 
 type WriteAttributesResponseRecord struct {
-	Status     uint8
+	Status     zcl.Status
 	Identifier zcl.AttributeID
 }
 
@@ -79,7 +85,7 @@ type ConfigureReporting struct {
 }
 
 type ConfigureReportingResponseRecord struct {
-	Status     uint8
+	Status     zcl.Status
 	Direction  uint8
 	Identifier zcl.AttributeID
 }
@@ -98,7 +104,7 @@ type ReadReportingConfiguration struct {
 }
 
 type ReadReportingConfigurationResponseRecord struct {
-	Status           uint8
+	Status           zcl.Status
 	Direction        uint8
 	Identifier       zcl.AttributeID
 	DataType         zcl.AttributeDataType   `bcincludeif:"Direction==0"`
@@ -123,7 +129,7 @@ type ReportAttributes struct {
 
 type DefaultResponse struct {
 	CommandIdentifier uint8
-	Status            uint8
+	Status            zcl.Status
 }
 
 type DiscoverAttributes struct {
@@ -166,7 +172,7 @@ type WriteAttributesStructured struct {
 }
 
 type WriteAttributesStructuredResponseRecord struct {
-	Status     uint8
+	Status     zcl.Status
 	Identifier zcl.AttributeID
 	Selector   Selector
 }
@@ -5,27 +5,27 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterGlobal(ReadAttributesID, &ReadAttributes{})
-	cr.RegisterGlobal(ReadAttributesResponseID, &ReadAttributesResponse{})
-	cr.RegisterGlobal(WriteAttributesID, &WriteAttributes{})
-	cr.RegisterGlobal(WriteAttributesUndividedID, &WriteAttributesUndivided{})
-	cr.RegisterGlobal(WriteAttributesResponseID, &WriteAttributesResponse{})
-	cr.RegisterGlobal(WriteAttributesNoResponseID, &WriteAttributesNoResponse{})
-	cr.RegisterGlobal(ConfigureReportingID, &ConfigureReporting{})
-	cr.RegisterGlobal(ConfigureReportingResponseID, &ConfigureReportingResponse{})
-	cr.RegisterGlobal(ReadReportingConfigurationID, &ReadReportingConfiguration{})
-	cr.RegisterGlobal(ReadReportingConfigurationResponseID, &ReadReportingConfigurationResponse{})
-	cr.RegisterGlobal(ReportAttributesID, &ReportAttributes{})
-	cr.RegisterGlobal(DefaultResponseID, &DefaultResponse{})
-	cr.RegisterGlobal(DiscoverAttributesID, &DiscoverAttributes{})
-	cr.RegisterGlobal(DiscoverAttributesResponseID, &DiscoverAttributesResponse{})
-	cr.RegisterGlobal(ReadAttributesStructuredID, &ReadAttributesStructured{})
-	cr.RegisterGlobal(WriteAttributesStructuredID, &WriteAttributesStructured{})
-	cr.RegisterGlobal(WriteAttributesStructuredResponseID, &WriteAttributesStructuredResponse{})
-	cr.RegisterGlobal(DiscoverCommandsReceivedID, &DiscoverCommandsReceived{})
-	cr.RegisterGlobal(DiscoverCommandsReceivedResponseID, &DiscoverCommandsReceivedResponse{})
-	cr.RegisterGlobal(DiscoverCommandsGeneratedID, &DiscoverCommandsGenerated{})
-	cr.RegisterGlobal(DiscoverCommandsGeneratedResponseID, &DiscoverCommandsGeneratedResponse{})
-	cr.RegisterGlobal(DiscoverAttributesExtendedID, &DiscoverAttributesExtended{})
-	cr.RegisterGlobal(DiscoverAttributesExtendedResponseID, &DiscoverAttributesExtendedResponse{})
+	cr.MustRegisterGlobal(ReadAttributesID, &ReadAttributes{})
+	cr.MustRegisterGlobal(ReadAttributesResponseID, &ReadAttributesResponse{})
+	cr.MustRegisterGlobal(WriteAttributesID, &WriteAttributes{})
+	cr.MustRegisterGlobal(WriteAttributesUndividedID, &WriteAttributesUndivided{})
+	cr.MustRegisterGlobal(WriteAttributesResponseID, &WriteAttributesResponse{})
+	cr.MustRegisterGlobal(WriteAttributesNoResponseID, &WriteAttributesNoResponse{})
+	cr.MustRegisterGlobal(ConfigureReportingID, &ConfigureReporting{})
+	cr.MustRegisterGlobal(ConfigureReportingResponseID, &ConfigureReportingResponse{})
+	cr.MustRegisterGlobal(ReadReportingConfigurationID, &ReadReportingConfiguration{})
+	cr.MustRegisterGlobal(ReadReportingConfigurationResponseID, &ReadReportingConfigurationResponse{})
+	cr.MustRegisterGlobal(ReportAttributesID, &ReportAttributes{})
+	cr.MustRegisterGlobal(DefaultResponseID, &DefaultResponse{})
+	cr.MustRegisterGlobal(DiscoverAttributesID, &DiscoverAttributes{})
+	cr.MustRegisterGlobal(DiscoverAttributesResponseID, &DiscoverAttributesResponse{})
+	cr.MustRegisterGlobal(ReadAttributesStructuredID, &ReadAttributesStructured{})
+	cr.MustRegisterGlobal(WriteAttributesStructuredID, &WriteAttributesStructured{})
+	cr.MustRegisterGlobal(WriteAttributesStructuredResponseID, &WriteAttributesStructuredResponse{})
+	cr.MustRegisterGlobal(DiscoverCommandsReceivedID, &DiscoverCommandsReceived{})
+	cr.MustRegisterGlobal(DiscoverCommandsReceivedResponseID, &DiscoverCommandsReceivedResponse{})
+	cr.MustRegisterGlobal(DiscoverCommandsGeneratedID, &DiscoverCommandsGenerated{})
+	cr.MustRegisterGlobal(DiscoverCommandsGeneratedResponseID, &DiscoverCommandsGeneratedResponse{})
+	cr.MustRegisterGlobal(DiscoverAttributesExtendedID, &DiscoverAttributesExtended{})
+	cr.MustRegisterGlobal(DiscoverAttributesExtendedResponseID, &DiscoverAttributesExtendedResponse{})
 }
@@ -0,0 +1,31 @@
+package basic
+
+import "github.com/shimmeringbee/zcl"
+
+/*
+ * Basic cluster attributes, as per 3.2.2.2 in ZCL Revision 8.
+ */
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: ZCLVersion, Name: "ZCLVersion", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Default: 0x08, Mandatory: true},
+	{ID: ApplicationVersion, Name: "ApplicationVersion", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Default: 0x00},
+	{ID: StackVersion, Name: "StackVersion", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Default: 0x00},
+	{ID: HWVersion, Name: "HWVersion", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Default: 0x00},
+	{ID: ManufacturerName, Name: "ManufacturerName", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: ModelIdentifier, Name: "ModelIdentifier", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: DateCode, Name: "DateCode", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: PowerSource, Name: "PowerSource", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Default: 0x00, Mandatory: true},
+	{ID: GenericDeviceClass, Name: "GenericDeviceClass", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Default: 0xff},
+	{ID: GenericDeviceType, Name: "GenericDeviceType", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Default: 0xff},
+	{ID: ProductCode, Name: "ProductCode", DataType: zcl.TypeStringOctet8, Access: zcl.AccessRead, Default: ""},
+	{ID: ProductURL, Name: "ProductURL", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: ManufacturerVersionDetails, Name: "ManufacturerVersionDetails", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: SerialNumber, Name: "SerialNumber", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: ProductLabel, Name: "ProductLabel", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+	{ID: LocationDescription, Name: "LocationDescription", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessReadWrite, Default: ""},
+	{ID: PhysicalEnvironment, Name: "PhysicalEnvironment", DataType: zcl.TypeEnum8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: DeviceEnabled, Name: "DeviceEnabled", DataType: zcl.TypeBoolean, Access: zcl.AccessReadWrite, Default: true},
+	{ID: AlarmMask, Name: "AlarmMask", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: DisableLocalConfig, Name: "DisableLocalConfig", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: SWBuildID, Name: "SWBuildID", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead, Default: ""},
+}
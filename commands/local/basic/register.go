@@ -0,0 +1,10 @@
+package basic
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.BasicId, zigbee.NoManufacturer, Attributes...)
+}
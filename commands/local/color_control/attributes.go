@@ -0,0 +1,44 @@
+package color_control
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: CurrentHue, Name: "CurrentHue", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x00, Maximum: 0xfe, Default: 0x00},
+	{ID: CurrentSaturation, Name: "CurrentSaturation", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x00, Maximum: 0xfe, Default: 0x00},
+	{ID: RemainingTime, Name: "RemainingTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: CurrentX, Name: "CurrentX", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x0000, Maximum: 0xfeff, Default: 0x616b, Mandatory: true},
+	{ID: CurrentY, Name: "CurrentY", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x0000, Maximum: 0xfeff, Default: 0x607d, Mandatory: true},
+	{ID: DriftCompensation, Name: "DriftCompensation", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0x04},
+	{ID: CompensationText, Name: "CompensationText", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessRead},
+	{ID: ColorTemperatureMireds, Name: "ColorTemperatureMireds", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x0000, Maximum: 0xfeff, Default: 0x00fa},
+	{ID: ColorMode, Name: "ColorMode", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0x02, Default: 0x01, Mandatory: true},
+	{ID: EnhancedCurrentHue, Name: "EnhancedCurrentHue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead | zcl.AccessScene, Minimum: 0x0000, Maximum: 0xffff, Default: 0x0000},
+	{ID: EnhancedColorMode, Name: "EnhancedColorMode", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0x03, Default: 0x01, Mandatory: true},
+	{ID: ColorLoopActive, Name: "ColorLoopActive", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead | zcl.AccessScene, Minimum: 0x00, Maximum: 0x01, Default: 0x00},
+	{ID: ColorLoopDirection, Name: "ColorLoopDirection", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead | zcl.AccessScene, Minimum: 0x00, Maximum: 0x01, Default: 0x00},
+	{ID: ColorLoopTime, Name: "ColorLoopTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead | zcl.AccessScene, Minimum: 0x0000, Maximum: 0xffff, Default: 0x0019},
+	{ID: ColorLoopStartEnhancedHue, Name: "ColorLoopStartEnhancedHue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xffff, Default: 0x2300},
+	{ID: ColorLoopStoredEnhancedHue, Name: "ColorLoopStoredEnhancedHue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xffff, Default: 0x0000},
+	{ID: ColorCapabilities, Name: "ColorCapabilities", DataType: zcl.TypeBitmap16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0x001f, Default: 0x0000, Mandatory: true},
+	{ID: ColorTempPhysicalMinMireds, Name: "ColorTempPhysicalMinMireds", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff, Default: 0x0000},
+	{ID: ColorTempPhysicalMaxMireds, Name: "ColorTempPhysicalMaxMireds", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff, Default: 0xfeff},
+	{ID: NumberOfPrimaries, Name: "NumberOfPrimaries", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0x06},
+	{ID: Primary1X, Name: "Primary1X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary1Y, Name: "Primary1Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary1Intensity, Name: "Primary1Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: Primary2X, Name: "Primary2X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary2Y, Name: "Primary2Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary2Intensity, Name: "Primary2Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: Primary3X, Name: "Primary3X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary3Y, Name: "Primary3Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary3Intensity, Name: "Primary3Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: Primary4X, Name: "Primary4X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary4Y, Name: "Primary4Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary4Intensity, Name: "Primary4Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: Primary5X, Name: "Primary5X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary5Y, Name: "Primary5Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary5Intensity, Name: "Primary5Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: Primary6X, Name: "Primary6X", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary6Y, Name: "Primary6Y", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xfeff},
+	{ID: Primary6Intensity, Name: "Primary6Intensity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+}
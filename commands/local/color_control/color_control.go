@@ -205,6 +205,8 @@ type ColorLoopSet struct {
 	StartHue        uint16
 }
 
+type StopMoveStep struct{}
+
 type MoveColorTemperature struct {
 	MoveMode                      MoveMode
 	Rate                          uint16
@@ -473,6 +473,30 @@ func Test_ColorLoopSet(t *testing.T) {
 	})
 }
 
+func Test_StopMoveStep(t *testing.T) {
+	t.Run("marshals and unmarshalls correctly", func(t *testing.T) {
+		expectedCommand := StopMoveStep{}
+		actualCommand := StopMoveStep{}
+
+		actualBytes, err := bytecodec.Marshal(&expectedCommand)
+		assert.NoError(t, err)
+		assert.Empty(t, actualBytes)
+
+		err = bytecodec.Unmarshal(actualBytes, &actualCommand)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCommand, actualCommand)
+	})
+
+	t.Run("the message is registered in the command registry", func(t *testing.T) {
+		cr := zcl.NewCommandRegistry()
+		Register(cr)
+
+		id, err := cr.GetLocalCommandIdentifier(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, &StopMoveStep{})
+		assert.NoError(t, err)
+		assert.Equal(t, StopMoveStepId, id)
+	})
+}
+
 func Test_MoveColorTemperature(t *testing.T) {
 	t.Run("marshals and unmarshalls correctly", func(t *testing.T) {
 		expectedCommand := MoveColorTemperature{
@@ -6,22 +6,25 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToHueId, &MoveToHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveHueId, &MoveHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepHueId, &StepHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToSaturationId, &MoveToSaturation{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveSaturationId, &MoveSaturation{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepSaturationId, &StepSaturation{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToHueAndSaturationId, &MoveToHueAndSaturation{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToColorId, &MoveToColor{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveColorId, &MoveColor{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepColorId, &StepColor{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToColorTemperatureId, &MoveToColorTemperature{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveToHueId, &EnhancedMoveToHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveHueId, &EnhancedMoveHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedStepHueId, &EnhancedStepHue{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveToHueAndSaturationId, &EnhancedMoveToHueAndSaturation{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, ColorLoopSetId, &ColorLoopSet{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveColorTemperatureId, &MoveColorTemperature{})
-	cr.RegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepColorTemperatureId, &StepColorTemperature{})
+	cr.MustRegisterAttributes(zcl.ColorControlId, zigbee.NoManufacturer, Attributes...)
+
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToHueId, &MoveToHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveHueId, &MoveHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepHueId, &StepHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToSaturationId, &MoveToSaturation{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveSaturationId, &MoveSaturation{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepSaturationId, &StepSaturation{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToHueAndSaturationId, &MoveToHueAndSaturation{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToColorId, &MoveToColor{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveColorId, &MoveColor{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepColorId, &StepColor{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToColorTemperatureId, &MoveToColorTemperature{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveToHueId, &EnhancedMoveToHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveHueId, &EnhancedMoveHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedStepHueId, &EnhancedStepHue{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, EnhancedMoveToHueAndSaturationId, &EnhancedMoveToHueAndSaturation{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, ColorLoopSetId, &ColorLoopSet{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StopMoveStepId, &StopMoveStep{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveColorTemperatureId, &MoveColorTemperature{})
+	cr.MustRegisterLocal(zcl.ColorControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepColorTemperatureId, &StepColorTemperature{})
 }
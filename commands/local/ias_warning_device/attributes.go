@@ -0,0 +1,7 @@
+package ias_warning_device
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: MaxDuration, Name: "MaxDuration", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x00f0, Mandatory: true},
+}
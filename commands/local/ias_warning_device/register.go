@@ -6,6 +6,8 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterLocal(zcl.IASWarningDevicesId, zigbee.NoManufacturer, zcl.ClientToServer, StartWarningId, &StartWarning{})
-	cr.RegisterLocal(zcl.IASWarningDevicesId, zigbee.NoManufacturer, zcl.ClientToServer, SquawkId, &Squawk{})
+	cr.MustRegisterAttributes(zcl.IASWarningDevicesId, zigbee.NoManufacturer, Attributes...)
+
+	cr.MustRegisterLocal(zcl.IASWarningDevicesId, zigbee.NoManufacturer, zcl.ClientToServer, StartWarningId, &StartWarning{})
+	cr.MustRegisterLocal(zcl.IASWarningDevicesId, zigbee.NoManufacturer, zcl.ClientToServer, SquawkId, &Squawk{})
 }
@@ -0,0 +1,13 @@
+package ias_zone
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: ZoneState, Name: "ZoneState", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Default: 0x00, Mandatory: true},
+	{ID: ZoneType, Name: "ZoneType", DataType: zcl.TypeEnum16, Access: zcl.AccessRead, Mandatory: true},
+	{ID: ZoneStatus, Name: "ZoneStatus", DataType: zcl.TypeBitmap16, Access: zcl.AccessRead, Default: 0x0000, Mandatory: true},
+	{ID: IASCIEAddress, Name: "IASCIEAddress", DataType: zcl.TypeIEEEAddress, Access: zcl.AccessReadWrite, Mandatory: true},
+	{ID: ZoneID, Name: "ZoneID", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0xff, Default: 0xff, Mandatory: true},
+	{ID: NumberOfZoneSensitivityLevelsSupported, Name: "NumberOfZoneSensitivityLevelsSupported", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead, Minimum: 0x02, Maximum: 0xff, Default: 0x02},
+	{ID: CurrentZoneSensitivityLevel, Name: "CurrentZoneSensitivityLevel", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+}
@@ -6,10 +6,14 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, ZoneEnrollResponseId, &ZoneEnrollResponse{})
-	cr.RegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, InitiateNormalOperationModeId, &InitiateNormalOperationMode{})
-	cr.RegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, InitiateTestModeId, &InitiateTestMode{})
+	cr.MustRegisterAttributes(zcl.IASZoneId, zigbee.NoManufacturer, Attributes...)
 
-	cr.RegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ZoneStatusChangeNotificationId, &ZoneStatusChangeNotification{})
-	cr.RegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ZoneEnrollRequestId, &ZoneEnrollRequest{})
+	cr.MustRegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, ZoneEnrollResponseId, &ZoneEnrollResponse{})
+	cr.MustRegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, InitiateNormalOperationModeId, &InitiateNormalOperationMode{})
+	cr.MustRegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, InitiateTestModeId, &InitiateTestMode{})
+
+	cr.MustRegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ZoneStatusChangeNotificationId, &ZoneStatusChangeNotification{})
+	cr.MustRegisterLocal(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ZoneEnrollRequestId, &ZoneEnrollRequest{})
+
+	cr.MustRegisterLocalResponse(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ZoneEnrollRequestId, ZoneEnrollResponseId)
 }
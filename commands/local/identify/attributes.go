@@ -0,0 +1,7 @@
+package identify
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: IdentifyTime, Name: "IdentifyTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0x0000, Mandatory: true},
+}
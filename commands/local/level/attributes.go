@@ -0,0 +1,13 @@
+package level
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: CurrentLevel, Name: "CurrentLevel", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport | zcl.AccessScene, Minimum: 0x00, Maximum: 0xff, Mandatory: true},
+	{ID: RemainingTime, Name: "RemainingTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0xffff, Default: 0x0000},
+	{ID: OnOffTransitionTime, Name: "OnOffTransitionTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xffff, Default: 0x0000},
+	{ID: OnLevel, Name: "OnLevel", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Minimum: 0x00, Maximum: 0xff, Default: 0xff},
+	{ID: OnTransitionTime, Name: "OnTransitionTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe},
+	{ID: OffTransitionTime, Name: "OffTransitionTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe},
+	{ID: DefaultMoveRate, Name: "DefaultMoveRate", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Minimum: 0x00, Maximum: 0xfe},
+}
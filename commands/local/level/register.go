@@ -6,12 +6,14 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToLevelId, &MoveToLevel{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveId, &Move{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepId, &Step{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StopId, &Stop{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToLevelWithOnOffId, &MoveToLevelWithOnOff{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveWithOnOffId, &MoveWithOnOff{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepWithOnOffId, &StepWithOnOff{})
-	cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StopWithOnOffId, &StopWithOnOff{})
+	cr.MustRegisterAttributes(zcl.LevelControlId, zigbee.NoManufacturer, Attributes...)
+
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToLevelId, &MoveToLevel{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveId, &Move{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepId, &Step{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StopId, &Stop{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveToLevelWithOnOffId, &MoveToLevelWithOnOff{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, MoveWithOnOffId, &MoveWithOnOff{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StepWithOnOffId, &StepWithOnOff{})
+	cr.MustRegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, StopWithOnOffId, &StopWithOnOff{})
 }
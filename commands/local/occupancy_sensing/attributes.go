@@ -0,0 +1,20 @@
+// Code generated by zclgen from occupancy-sensing.xml. DO NOT EDIT.
+
+package occupancy_sensing
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: Occupancy, Name: "Occupancy", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadReport, Minimum: 0x00, Maximum: 0x01, Mandatory: true},
+	{ID: OccupancySensorType, Name: "OccupancySensorType", DataType: zcl.TypeEnum8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0xfe, Mandatory: true},
+	{ID: OccupancySensorTypeBitmap, Name: "OccupancySensorTypeBitmap", DataType: zcl.TypeBitmap8, Access: zcl.AccessRead, Minimum: 0x00, Maximum: 0x07, Mandatory: true},
+	{ID: PIROccupiedToUnoccupiedDelay, Name: "PIROccupiedToUnoccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: PIRUnoccupiedToOccupiedDelay, Name: "PIRUnoccupiedToOccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: PIRUnoccupiedToOccupiedThreshold, Name: "PIRUnoccupiedToOccupiedThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Minimum: 0x01, Maximum: 0xfe, Default: 0x01},
+	{ID: UltrasonicOccupiedToUnoccupiedDelay, Name: "UltrasonicOccupiedToUnoccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: UltrasonicUnoccupiedToOccupiedDelay, Name: "UltrasonicUnoccupiedToOccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: UltrasonicUnoccupiedToOccupiedThreshold, Name: "UltrasonicUnoccupiedToOccupiedThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Minimum: 0x01, Maximum: 0xfe, Default: 0x01},
+	{ID: PhysicalContactOccupiedToUnoccupiedDelay, Name: "PhysicalContactOccupiedToUnoccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: PhysicalContactUnoccupiedToOccupiedDelay, Name: "PhysicalContactUnoccupiedToOccupiedDelay", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Minimum: 0x0000, Maximum: 0xfffe, Default: 0x0000},
+	{ID: PhysicalContactUnoccupiedToOccupiedThreshold, Name: "PhysicalContactUnoccupiedToOccupiedThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Minimum: 0x01, Maximum: 0xfe, Default: 0x01},
+}
@@ -0,0 +1,3 @@
+package occupancy_sensing
+
+//go:generate go run ../../../cmd/zclgen -xml ../../../cmd/zclgen/testdata/occupancy-sensing.xml
@@ -0,0 +1,20 @@
+// Code generated by zclgen from occupancy-sensing.xml. DO NOT EDIT.
+
+package occupancy_sensing
+
+import "github.com/shimmeringbee/zcl"
+
+const (
+	Occupancy                                    = zcl.AttributeID(0x0000)
+	OccupancySensorType                          = zcl.AttributeID(0x0001)
+	OccupancySensorTypeBitmap                    = zcl.AttributeID(0x0002)
+	PIROccupiedToUnoccupiedDelay                 = zcl.AttributeID(0x0010)
+	PIRUnoccupiedToOccupiedDelay                 = zcl.AttributeID(0x0011)
+	PIRUnoccupiedToOccupiedThreshold             = zcl.AttributeID(0x0012)
+	UltrasonicOccupiedToUnoccupiedDelay          = zcl.AttributeID(0x0020)
+	UltrasonicUnoccupiedToOccupiedDelay          = zcl.AttributeID(0x0021)
+	UltrasonicUnoccupiedToOccupiedThreshold      = zcl.AttributeID(0x0022)
+	PhysicalContactOccupiedToUnoccupiedDelay     = zcl.AttributeID(0x0030)
+	PhysicalContactUnoccupiedToOccupiedDelay     = zcl.AttributeID(0x0031)
+	PhysicalContactUnoccupiedToOccupiedThreshold = zcl.AttributeID(0x0032)
+)
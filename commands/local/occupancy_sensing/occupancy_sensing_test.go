@@ -0,0 +1,19 @@
+// Code generated by zclgen from occupancy-sensing.xml. DO NOT EDIT.
+
+package occupancy_sensing
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Attributes(t *testing.T) {
+	t.Run("the attributes are registered in the command registry", func(t *testing.T) {
+		cr := zcl.NewCommandRegistry()
+		Register(cr)
+
+		assert.Equal(t, Attributes, cr.Attributes(zcl.OccupancySensingId, zigbee.NoManufacturer))
+	})
+}
@@ -0,0 +1,10 @@
+package onoff
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: OnOff, Name: "OnOff", DataType: zcl.TypeBoolean, Access: zcl.AccessReadReport | zcl.AccessScene, Default: false, Mandatory: true},
+	{ID: GlobalSceneControl, Name: "GlobalSceneControl", DataType: zcl.TypeBoolean, Access: zcl.AccessRead, Default: true},
+	{ID: OnTime, Name: "OnTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0x0000},
+	{ID: OffWaitTime, Name: "OffWaitTime", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0x0000},
+}
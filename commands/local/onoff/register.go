@@ -6,10 +6,12 @@ import (
 )
 
 func Register(cr *zcl.CommandRegistry) {
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OffId, &Off{})
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnId, &On{})
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, ToggleId, &Toggle{})
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OffWithEffectId, &OffWithEffect{})
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnWithRecallGlobalSceneId, &OnWithRecallGlobalScene{})
-	cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnWithTimedOffId, &OnWithTimedOff{})
+	cr.MustRegisterAttributes(zcl.OnOffId, zigbee.NoManufacturer, Attributes...)
+
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OffId, &Off{})
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnId, &On{})
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, ToggleId, &Toggle{})
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OffWithEffectId, &OffWithEffect{})
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnWithRecallGlobalSceneId, &OnWithRecallGlobalScene{})
+	cr.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, OnWithTimedOffId, &OnWithTimedOff{})
 }
@@ -0,0 +1,63 @@
+package power_configuration
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: MainsVoltage, Name: "MainsVoltage", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead},
+	{ID: MainsFrequency, Name: "MainsFrequency", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+	{ID: MainsAlarmMask, Name: "MainsAlarmMask", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Minimum: 0x00, Maximum: 0x03, Default: 0x00},
+	{ID: MainsVoltageMinThreshold, Name: "MainsVoltageMinThreshold", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0x0000},
+	{ID: MainsVoltageMaxThreshold, Name: "MainsVoltageMaxThreshold", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0xffff},
+	{ID: MainsVoltageDwellTripPoint, Name: "MainsVoltageDwellTripPoint", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite, Default: 0x0000},
+	{ID: BatteryVoltage, Name: "BatteryVoltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport},
+	{ID: BatteryPercentageRemaining, Name: "BatteryPercentageRemaining", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport, Minimum: 0x00, Maximum: 0xff, Default: 0x00},
+	{ID: BatteryManufacturer, Name: "BatteryManufacturer", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessReadWrite, Default: ""},
+	{ID: BatterySize, Name: "BatterySize", DataType: zcl.TypeEnum8, Access: zcl.AccessReadWrite, Default: 0xff},
+	{ID: BatteryAHrRating, Name: "BatteryAHrRating", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite},
+	{ID: BatteryQuantity, Name: "BatteryQuantity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatteryRatedVoltage, Name: "BatteryRatedVoltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatteryAlarmMask, Name: "BatteryAlarmMask", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryVoltageMinThreshold, Name: "BatteryVoltageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryVoltageThreshold1, Name: "BatteryVoltageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryVoltageThreshold2, Name: "BatteryVoltageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryVoltageThreshold3, Name: "BatteryVoltageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryPercentageMinThreshold, Name: "BatteryPercentageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryPercentageThreshold1, Name: "BatteryPercentageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryPercentageThreshold2, Name: "BatteryPercentageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryPercentageThreshold3, Name: "BatteryPercentageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatteryAlarmState, Name: "BatteryAlarmState", DataType: zcl.TypeBitmap32, Access: zcl.AccessReadReport, Default: 0x00000000},
+	{ID: BatterySource2Voltage, Name: "BatterySource2Voltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport},
+	{ID: BatterySource2PercentageRemaining, Name: "BatterySource2PercentageRemaining", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport, Minimum: 0x00, Maximum: 0xff, Default: 0x00},
+	{ID: BatterySource2Manufacturer, Name: "BatterySource2Manufacturer", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessReadWrite, Default: ""},
+	{ID: BatterySource2Size, Name: "BatterySource2Size", DataType: zcl.TypeEnum8, Access: zcl.AccessReadWrite, Default: 0xff},
+	{ID: BatterySource2AHrRating, Name: "BatterySource2AHrRating", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite},
+	{ID: BatterySource2Quantity, Name: "BatterySource2Quantity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatterySource2RatedVoltage, Name: "BatterySource2RatedVoltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatterySource2AlarmMask, Name: "BatterySource2AlarmMask", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2VoltageMinThreshold, Name: "BatterySource2VoltageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2VoltageThreshold1, Name: "BatterySource2VoltageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2VoltageThreshold2, Name: "BatterySource2VoltageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2VoltageThreshold3, Name: "BatterySource2VoltageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2PercentageMinThreshold, Name: "BatterySource2PercentageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2PercentageThreshold1, Name: "BatterySource2PercentageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2PercentageThreshold2, Name: "BatterySource2PercentageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2PercentageThreshold3, Name: "BatterySource2PercentageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource2AlarmState, Name: "BatterySource2AlarmState", DataType: zcl.TypeBitmap32, Access: zcl.AccessReadReport, Default: 0x00000000},
+	{ID: BatterySource3Voltage, Name: "BatterySource3Voltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport},
+	{ID: BatterySource3PercentageRemaining, Name: "BatterySource3PercentageRemaining", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport, Minimum: 0x00, Maximum: 0xff, Default: 0x00},
+	{ID: BatterySource3Manufacturer, Name: "BatterySource3Manufacturer", DataType: zcl.TypeStringCharacter8, Access: zcl.AccessReadWrite, Default: ""},
+	{ID: BatterySource3Size, Name: "BatterySource3Size", DataType: zcl.TypeEnum8, Access: zcl.AccessReadWrite, Default: 0xff},
+	{ID: BatterySource3AHrRating, Name: "BatterySource3AHrRating", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadWrite},
+	{ID: BatterySource3Quantity, Name: "BatterySource3Quantity", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatterySource3RatedVoltage, Name: "BatterySource3RatedVoltage", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite},
+	{ID: BatterySource3AlarmMask, Name: "BatterySource3AlarmMask", DataType: zcl.TypeBitmap8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3VoltageMinThreshold, Name: "BatterySource3VoltageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3VoltageThreshold1, Name: "BatterySource3VoltageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3VoltageThreshold2, Name: "BatterySource3VoltageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3VoltageThreshold3, Name: "BatterySource3VoltageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3PercentageMinThreshold, Name: "BatterySource3PercentageMinThreshold", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3PercentageThreshold1, Name: "BatterySource3PercentageThreshold1", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3PercentageThreshold2, Name: "BatterySource3PercentageThreshold2", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3PercentageThreshold3, Name: "BatterySource3PercentageThreshold3", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Default: 0x00},
+	{ID: BatterySource3AlarmState, Name: "BatterySource3AlarmState", DataType: zcl.TypeBitmap32, Access: zcl.AccessReadReport, Default: 0x00000000},
+}
@@ -0,0 +1,10 @@
+package power_configuration
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.PowerConfigurationId, zigbee.NoManufacturer, Attributes...)
+}
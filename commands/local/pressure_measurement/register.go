@@ -0,0 +1,10 @@
+package pressure_measurement
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.PressureMeasurementId, zigbee.NoManufacturer, Attributes...)
+}
@@ -0,0 +1,10 @@
+package relative_humidity_measurement
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: MeasuredValue, Name: "MeasuredValue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: MinMeasuredValue, Name: "MinMeasuredValue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0000, Maximum: 0x270f, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: MaxMeasuredValue, Name: "MaxMeasuredValue", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessRead, Minimum: 0x0001, Maximum: 0x2710, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: Tolerance, Name: "Tolerance", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport, Minimum: 0x0000, Maximum: 0x0800},
+}
@@ -0,0 +1,10 @@
+package relative_humidity_measurement
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.RelativeHumidityMeasurementId, zigbee.NoManufacturer, Attributes...)
+}
@@ -0,0 +1,10 @@
+package temperature_measurement
+
+import "github.com/shimmeringbee/zcl"
+
+var Attributes = []zcl.AttributeDefinition{
+	{ID: MeasuredValue, Name: "MeasuredValue", DataType: zcl.TypeSignedInt16, Access: zcl.AccessReadReport, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: MinMeasuredValue, Name: "MinMeasuredValue", DataType: zcl.TypeSignedInt16, Access: zcl.AccessRead, Minimum: -27315, Maximum: 32766, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: MaxMeasuredValue, Name: "MaxMeasuredValue", DataType: zcl.TypeSignedInt16, Access: zcl.AccessRead, Minimum: -27314, Maximum: 32767, Default: zcl.NonValue{}, Mandatory: true},
+	{ID: Tolerance, Name: "Tolerance", DataType: zcl.TypeUnsignedInt16, Access: zcl.AccessReadReport, Minimum: 0x0000, Maximum: 0x0800},
+}
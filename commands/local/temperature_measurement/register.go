@@ -0,0 +1,10 @@
+package temperature_measurement
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+)
+
+func Register(cr *zcl.CommandRegistry) {
+	cr.MustRegisterAttributes(zcl.TemperatureMeasurementId, zigbee.NoManufacturer, Attributes...)
+}
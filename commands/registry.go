@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zcl/commands/local/basic"
+	"github.com/shimmeringbee/zcl/commands/local/color_control"
+	"github.com/shimmeringbee/zcl/commands/local/ias_warning_device"
+	"github.com/shimmeringbee/zcl/commands/local/ias_zone"
+	"github.com/shimmeringbee/zcl/commands/local/identify"
+	"github.com/shimmeringbee/zcl/commands/local/level"
+	"github.com/shimmeringbee/zcl/commands/local/occupancy_sensing"
+	"github.com/shimmeringbee/zcl/commands/local/onoff"
+	"github.com/shimmeringbee/zcl/commands/local/power_configuration"
+	"github.com/shimmeringbee/zcl/commands/local/pressure_measurement"
+	"github.com/shimmeringbee/zcl/commands/local/relative_humidity_measurement"
+	"github.com/shimmeringbee/zcl/commands/local/temperature_measurement"
+	"github.com/shimmeringbee/zigbee"
+)
+
+var localRegisters = []func(*zcl.CommandRegistry){
+	basic.Register,
+	color_control.Register,
+	ias_warning_device.Register,
+	ias_zone.Register,
+	identify.Register,
+	level.Register,
+	occupancy_sensing.Register,
+	onoff.Register,
+	power_configuration.Register,
+	pressure_measurement.Register,
+	relative_humidity_measurement.Register,
+	temperature_measurement.Register,
+}
+
+type options struct {
+	includeClusters      map[zigbee.ClusterID]bool
+	excludeClusters      map[zigbee.ClusterID]bool
+	includeManufacturers map[zigbee.ManufacturerCode]bool
+	excludeManufacturers map[zigbee.ManufacturerCode]bool
+}
+
+type Option func(*options)
+
+// IncludeClusters limits the local commands registered to those of the clusters provided, may be repeated.
+func IncludeClusters(clusterIDs ...zigbee.ClusterID) Option {
+	return func(o *options) {
+		for _, clusterID := range clusterIDs {
+			o.includeClusters[clusterID] = true
+		}
+	}
+}
+
+func ExcludeClusters(clusterIDs ...zigbee.ClusterID) Option {
+	return func(o *options) {
+		for _, clusterID := range clusterIDs {
+			o.excludeClusters[clusterID] = true
+		}
+	}
+}
+
+// IncludeManufacturers limits the manufacturer specific local commands registered to those of the manufacturers
+// provided, commands without a manufacturer are always registered.
+func IncludeManufacturers(manufacturers ...zigbee.ManufacturerCode) Option {
+	return func(o *options) {
+		for _, manufacturer := range manufacturers {
+			o.includeManufacturers[manufacturer] = true
+		}
+	}
+}
+
+func ExcludeManufacturers(manufacturers ...zigbee.ManufacturerCode) Option {
+	return func(o *options) {
+		for _, manufacturer := range manufacturers {
+			o.excludeManufacturers[manufacturer] = true
+		}
+	}
+}
+
+func (o options) clusterPermitted(clusterID zigbee.ClusterID) bool {
+	if len(o.includeClusters) > 0 && !o.includeClusters[clusterID] {
+		return false
+	}
+
+	return !o.excludeClusters[clusterID]
+}
+
+func (o options) manufacturerPermitted(manufacturer zigbee.ManufacturerCode) bool {
+	if manufacturer == zigbee.NoManufacturer {
+		return true
+	}
+
+	if len(o.includeManufacturers) > 0 && !o.includeManufacturers[manufacturer] {
+		return false
+	}
+
+	return !o.excludeManufacturers[manufacturer]
+}
+
+// DefaultRegistry returns a CommandRegistry populated with all global commands, and all local commands of the
+// clusters supported by this module.
+func DefaultRegistry(opts ...Option) *zcl.CommandRegistry {
+	o := options{
+		includeClusters:      map[zigbee.ClusterID]bool{},
+		excludeClusters:      map[zigbee.ClusterID]bool{},
+		includeManufacturers: map[zigbee.ManufacturerCode]bool{},
+		excludeManufacturers: map[zigbee.ManufacturerCode]bool{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	all := zcl.NewCommandRegistry()
+	all.SetConflictPolicy(zcl.ConflictPanic)
+
+	for _, register := range localRegisters {
+		register(all)
+	}
+
+	cr := zcl.NewCommandRegistry()
+	global.Register(cr)
+
+	for _, clusterID := range all.LocalClusters() {
+		if !o.clusterPermitted(clusterID) {
+			continue
+		}
+
+		for _, manufacturer := range all.LocalManufacturers(clusterID) {
+			if !o.manufacturerPermitted(manufacturer) {
+				continue
+			}
+
+			for _, direction := range all.LocalDirections(clusterID, manufacturer) {
+				for _, command := range all.LocalCommands(clusterID, manufacturer, direction) {
+					cr.MustRegisterLocalDefinition(clusterID, manufacturer, direction, command.Identifier, command.Definition)
+				}
+			}
+
+			for _, direction := range all.LocalDirections(clusterID, manufacturer) {
+				for _, command := range all.LocalCommands(clusterID, manufacturer, direction) {
+					if response, found := all.LocalCommandResponse(clusterID, manufacturer, direction, command.Identifier); found {
+						cr.MustRegisterLocalResponse(clusterID, manufacturer, direction, command.Identifier, response)
+					}
+				}
+			}
+		}
+	}
+
+	for _, clusterID := range all.AttributeClusters() {
+		if !o.clusterPermitted(clusterID) {
+			continue
+		}
+
+		for _, manufacturer := range all.AttributeManufacturers(clusterID) {
+			if o.manufacturerPermitted(manufacturer) {
+				cr.MustRegisterAttributes(clusterID, manufacturer, all.Attributes(clusterID, manufacturer)...)
+			}
+		}
+	}
+
+	return cr
+}
@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zcl/commands/local/ias_zone"
+	"github.com/shimmeringbee/zcl/commands/local/level"
+	"github.com/shimmeringbee/zcl/commands/local/onoff"
+	"github.com/shimmeringbee/zcl/commands/local/power_configuration"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const modulePath = "github.com/shimmeringbee/zcl/commands/"
+
+func commandIdentifierConstants(t *testing.T, dir string) []string {
+	return typedConstants(t, dir, "CommandIdentifier")
+}
+
+func typedConstants(t *testing.T, dir string, typeName string) []string {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	assert.NoError(t, err)
+
+	var names []string
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				spec, ok := node.(*ast.ValueSpec)
+				if !ok {
+					return true
+				}
+
+				isTyped := isSelector(spec.Type, "zcl", typeName)
+
+				for _, value := range spec.Values {
+					if call, ok := value.(*ast.CallExpr); ok && isSelector(call.Fun, "zcl", typeName) {
+						isTyped = true
+					}
+				}
+
+				if isTyped {
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return names
+}
+
+func isSelector(expr ast.Expr, pkg string, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+
+	return ok && ident.Name == pkg && selector.Sel.Name == name
+}
+
+func registeredTypeNames(cr *zcl.CommandRegistry) map[string]bool {
+	names := map[string]bool{}
+
+	for _, command := range cr.GlobalCommands() {
+		names[command.Type.Elem().PkgPath()+"."+command.Type.Elem().Name()] = true
+	}
+
+	for _, clusterID := range cr.LocalClusters() {
+		for _, manufacturer := range cr.LocalManufacturers(clusterID) {
+			for _, direction := range cr.LocalDirections(clusterID, manufacturer) {
+				for _, command := range cr.LocalCommands(clusterID, manufacturer, direction) {
+					names[command.Type.Elem().PkgPath()+"."+command.Type.Elem().Name()] = true
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func Test_DefaultRegistry(t *testing.T) {
+	t.Run("every local cluster package is included", func(t *testing.T) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "registry.go", nil, parser.ImportsOnly)
+		assert.NoError(t, err)
+
+		imports := map[string]bool{}
+		for _, spec := range file.Imports {
+			imports[strings.Trim(spec.Path.Value, `"`)] = true
+		}
+
+		dirs, err := ioutil.ReadDir("local")
+		assert.NoError(t, err)
+
+		for _, dir := range dirs {
+			if dir.IsDir() {
+				assert.True(t, imports[modulePath+"local/"+dir.Name()], "package %s is not included in DefaultRegistry", dir.Name())
+			}
+		}
+	})
+
+	t.Run("every command identifier declared by a package is registered", func(t *testing.T) {
+		registered := registeredTypeNames(DefaultRegistry())
+
+		dirs, err := filepath.Glob("local/*")
+		assert.NoError(t, err)
+
+		dirs = append(dirs, "global")
+
+		for _, dir := range dirs {
+			for _, constant := range commandIdentifierConstants(t, dir) {
+				typeName := strings.TrimSuffix(strings.TrimSuffix(constant, "ID"), "Id")
+				assert.True(t, registered[modulePath+dir+"."+typeName], "command %s of %s is not registered", typeName, dir)
+			}
+		}
+	})
+
+	t.Run("every attribute declared by a package is registered with its name", func(t *testing.T) {
+		for _, register := range localRegisters {
+			pkgPath := strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(register).Pointer()).Name(), ".Register")
+			dir := strings.TrimPrefix(pkgPath, modulePath)
+
+			cr := zcl.NewCommandRegistry()
+			register(cr)
+
+			registered := map[string]bool{}
+
+			for _, clusterID := range cr.AttributeClusters() {
+				for _, definition := range cr.Attributes(clusterID, zigbee.NoManufacturer) {
+					registered[definition.Name] = true
+				}
+			}
+
+			for _, constant := range typedConstants(t, dir, "AttributeID") {
+				assert.True(t, registered[constant], "attribute %s of %s is not registered", constant, dir)
+			}
+		}
+	})
+
+	t.Run("every attribute definition has a data type and a valid default and range", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		for _, clusterID := range cr.AttributeClusters() {
+			for _, definition := range cr.Attributes(clusterID, zigbee.NoManufacturer) {
+				qualifiedName := cr.QualifiedAttributeName(clusterID, zigbee.NoManufacturer, definition.ID)
+
+				assert.NotEqual(t, zcl.TypeNull, definition.DataType, "attribute %s has no data type", qualifiedName)
+				assert.True(t, definition.Readable(), "attribute %s is not readable", qualifiedName)
+
+				_, err := definition.DefaultValue()
+				assert.NoError(t, err, "attribute %s has an invalid default", qualifiedName)
+
+				for _, limit := range []interface{}{definition.Minimum, definition.Maximum} {
+					if limit != nil {
+						_, err := zcl.NewAttributeDataTypeValue(definition.DataType, limit)
+						assert.NoError(t, err, "attribute %s has an invalid range", qualifiedName)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("attribute metadata is available from the default registry", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		definition, found := cr.Attribute(zcl.PowerConfigurationId, zigbee.NoManufacturer, power_configuration.BatteryPercentageRemaining)
+		assert.True(t, found)
+		assert.Equal(t, zcl.TypeUnsignedInt8, definition.DataType)
+		assert.True(t, definition.Reportable())
+
+		change, err := definition.ReportableChange(2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), change)
+	})
+
+	t.Run("attribute and command names can be resolved", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		assert.Equal(t, "TemperatureMeasurement.MeasuredValue", cr.QualifiedAttributeName(zcl.TemperatureMeasurementId, zigbee.NoManufacturer, 0x0000))
+
+		name, found := cr.LocalCommandName(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, 0x42)
+		assert.True(t, found)
+		assert.Equal(t, "OnWithTimedOff", name)
+
+		name, found = cr.GlobalCommandName(0x0b)
+		assert.True(t, found)
+		assert.Equal(t, "DefaultResponse", name)
+	})
+
+	t.Run("local command responses are available from the default registry", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		response, found := cr.LocalCommandResponse(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ServerToClient, ias_zone.ZoneEnrollRequestId)
+		assert.True(t, found)
+		assert.Equal(t, ias_zone.ZoneEnrollResponseId, response)
+
+		assert.True(t, cr.IsLocalCommandResponse(zcl.IASZoneId, zigbee.NoManufacturer, zcl.ClientToServer, ias_zone.ZoneEnrollResponseId))
+	})
+
+	t.Run("messages round trip through JSON", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		messages := []zcl.Message{
+			{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: 0x10,
+				ClusterID:           zcl.BasicId,
+				CommandIdentifier:   global.ReadAttributesResponseID,
+				Command: &global.ReadAttributesResponse{
+					Records: []global.ReadAttributeResponseRecord{
+						{Identifier: 0x0005, Status: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeStringCharacter8, Value: "lumi.sensor"}},
+						{Identifier: 0x0006, Status: uint8(zcl.StatusUnsupportedAttribute)},
+					},
+				},
+			},
+			{
+				FrameType:         zcl.FrameGlobal,
+				Direction:         zcl.ClientToServer,
+				ClusterID:         zcl.TemperatureMeasurementId,
+				CommandIdentifier: global.ConfigureReportingID,
+				Command: &global.ConfigureReporting{
+					Records: []global.ConfigureReportingRecord{
+						{Identifier: 0x0000, DataType: zcl.TypeSignedInt16, MinimumInterval: 60, MaximumInterval: 300, ReportableChange: &zcl.AttributeDataValue{Value: int64(50)}},
+					},
+				},
+			},
+			{
+				FrameType:         zcl.FrameLocal,
+				Direction:         zcl.ClientToServer,
+				ClusterID:         zcl.OnOffId,
+				CommandIdentifier: 0x42,
+				Command:           &onoff.OnWithTimedOff{OnOffControl: onoff.OnOffControl{AcceptOnlyWhenOn: true}, OnTime: 300, OffWaitTime: 10},
+			},
+		}
+
+		for _, expected := range messages {
+			data, err := cr.MarshalMessageJSON(expected)
+			assert.NoError(t, err)
+
+			actual, err := cr.UnmarshalMessageJSON(data)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("messages are described with cluster, command and flag names", func(t *testing.T) {
+		cr := DefaultRegistry()
+
+		message := zcl.Message{
+			FrameType:         zcl.FrameLocal,
+			Direction:         zcl.ServerToClient,
+			ClusterID:         zcl.IASZoneId,
+			SourceEndpoint:    1,
+			CommandIdentifier: ias_zone.ZoneStatusChangeNotificationId,
+			Command:           &ias_zone.ZoneStatusChangeNotification{Alarm1: true, BatteryLow: true, ZoneID: 3},
+		}
+
+		assert.Equal(t, "local ZoneStatusChangeNotification(0x00) server_to_client cluster=IASZone(0x0500) tsn=0 endpoints=1->0 {Flags=BatteryLow|Alarm1 ExtendedStatus=0 ZoneID=3 Delay=0}", cr.Describe(message).String())
+	})
+
+	t.Run("registering a cluster over a conflicting command follows the conflict policy", func(t *testing.T) {
+		cr := zcl.NewCommandRegistry()
+		assert.NoError(t, cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, level.MoveToLevelId, &onoff.Off{}))
+
+		assert.NotPanics(t, func() {
+			level.Register(cr)
+		})
+
+		command, err := cr.GetLocalCommand(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, level.MoveToLevelId)
+		assert.NoError(t, err)
+		assert.IsType(t, &level.MoveToLevel{}, command)
+
+		cr = zcl.NewCommandRegistry()
+		cr.SetConflictPolicy(zcl.ConflictPanic)
+		assert.NoError(t, cr.RegisterLocal(zcl.LevelControlId, zigbee.NoManufacturer, zcl.ClientToServer, level.MoveToLevelId, &onoff.Off{}))
+
+		assert.Panics(t, func() {
+			level.Register(cr)
+		})
+	})
+
+	t.Run("clusters can be included and excluded", func(t *testing.T) {
+		cr := DefaultRegistry(IncludeClusters(zcl.OnOffId, zcl.LevelControlId), ExcludeClusters(zcl.LevelControlId))
+
+		assert.Equal(t, []zigbee.ClusterID{zcl.OnOffId}, cr.LocalClusters())
+		assert.Equal(t, []zigbee.ClusterID{zcl.OnOffId}, cr.AttributeClusters())
+		assert.NotEmpty(t, cr.GlobalCommands())
+	})
+
+	t.Run("manufacturer specific commands and attributes can be included and excluded", func(t *testing.T) {
+		type ManufacturerCommand struct{}
+
+		original := localRegisters
+		defer func() { localRegisters = original }()
+
+		localRegisters = append(append([]func(*zcl.CommandRegistry){}, original...), func(cr *zcl.CommandRegistry) {
+			cr.MustRegisterLocal(zcl.OnOffId, 0x1234, zcl.ClientToServer, 0x01, &ManufacturerCommand{})
+			cr.MustRegisterLocal(zcl.OnOffId, 0x5678, zcl.ClientToServer, 0x01, &ManufacturerCommand{})
+			cr.MustRegisterAttributes(zcl.OnOffId, 0x1234, zcl.AttributeDefinition{ID: 0x0001, Name: "ManufacturerAttribute", DataType: zcl.TypeBoolean})
+		})
+
+		cr := DefaultRegistry()
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer, 0x1234, 0x5678}, cr.LocalManufacturers(zcl.OnOffId))
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer, 0x1234}, cr.AttributeManufacturers(zcl.OnOffId))
+
+		cr = DefaultRegistry(ExcludeManufacturers(0x1234))
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer, 0x5678}, cr.LocalManufacturers(zcl.OnOffId))
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer}, cr.AttributeManufacturers(zcl.OnOffId))
+
+		cr = DefaultRegistry(IncludeManufacturers(0x1234))
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer, 0x1234}, cr.LocalManufacturers(zcl.OnOffId))
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer, 0x1234}, cr.AttributeManufacturers(zcl.OnOffId))
+
+		_, err := cr.GetLocalCommand(zcl.OnOffId, 0x5678, zcl.ClientToServer, 0x01)
+		assert.Error(t, err)
+	})
+}
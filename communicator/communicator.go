@@ -14,6 +14,16 @@ import (
 type MessageWithSource struct {
 	SourceAddress zigbee.IEEEAddress
 	Message       zcl.Message
+
+	transaction *inboundTransaction
+}
+
+// SetDefaultResponseStatus sets the status to be used if the communicator sends an automatic Default Response for this
+// message, it has no effect if automatic Default Responses are not enabled.
+func (m MessageWithSource) SetDefaultResponseStatus(status zcl.Status) {
+	if m.transaction != nil {
+		m.transaction.setStatus(status)
+	}
 }
 
 var matchId = new(uint64)
@@ -38,6 +48,7 @@ type Match struct {
 	id       uint64
 	matcher  Matcher
 	callback func(source MessageWithSource)
+	inline   bool
 }
 
 type communicator struct {
@@ -46,33 +57,102 @@ type communicator struct {
 
 	mutex   *sync.RWMutex
 	matches map[uint64]Match
+
+	automaticDefaultResponse bool
+	inboundMutex             *sync.Mutex
+	inbound                  map[inboundKey]*inboundTransaction
+	inboundPending           *sync.WaitGroup
+
+	unmarshalOptions []zcl.UnmarshalOption
+
+	defaultRetryPolicy RetryPolicy
+
+	dispatcher *dispatcher
+	stopMutex  *sync.RWMutex
+	stopped    uint32
+
+	sequences                    *sequenceAllocator
+	automaticTransactionSequence bool
 }
 
-func NewCommunicator(provider zigbee.Provider, registry *zcl.CommandRegistry) Communicator {
-	return &communicator{
+func NewCommunicator(provider zigbee.Provider, registry *zcl.CommandRegistry, options ...Option) Communicator {
+	c := &communicator{
 		Provider:        provider,
 		CommandRegistry: registry,
 		mutex:           &sync.RWMutex{},
 		matches:         map[uint64]Match{},
+		inboundMutex:    &sync.Mutex{},
+		inbound:         map[inboundKey]*inboundTransaction{},
+		inboundPending:  &sync.WaitGroup{},
+		stopMutex:       &sync.RWMutex{},
+		sequences:       newSequenceAllocator(),
 	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
 }
 
 func (c *communicator) ProcessIncomingMessage(msg zigbee.NodeIncomingMessageEvent) error {
-	message, err := c.CommandRegistry.Unmarshal(msg.ApplicationMessage)
+	if atomic.LoadUint32(&c.stopped) != 0 {
+		return ErrCommunicatorStopped
+	}
+
+	message, err := c.CommandRegistry.Unmarshal(msg.ApplicationMessage, c.unmarshalOptions...)
 
 	if err != nil {
+		if c.automaticDefaultResponse {
+			c.rejectUnknownCommand(msg)
+		}
+
 		return fmt.Errorf("failed to unmarshal incomming ZCL message: %w", err)
 	}
 
+	var transaction *inboundTransaction
+
+	if c.automaticDefaultResponse {
+		transaction = c.beginInboundTransaction(msg.IEEEAddress, message)
+	}
+
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+
+	var matches []Match
 
 	for _, match := range c.matches {
 		if match.matcher(msg.IEEEAddress, msg.ApplicationMessage, message) {
-			go match.callback(MessageWithSource{
-				SourceAddress: msg.IEEEAddress,
-				Message:       message,
-			})
+			matches = append(matches, match)
+		}
+	}
+
+	c.mutex.RUnlock()
+
+	if _, isUnknown := message.Command.(*zcl.UnknownCommand); isUnknown && transaction != nil && len(matches) == 0 {
+		transaction.setStatus(unsupportedCommandStatus(message))
+	}
+
+	source := MessageWithSource{
+		SourceAddress: msg.IEEEAddress,
+		Message:       message,
+		transaction:   transaction,
+	}
+
+	wg, err := c.callbacks(source, matches)
+
+	if err != nil {
+		if transaction != nil {
+			c.endInboundTransaction(msg.IEEEAddress, message, transaction)
+		}
+
+		return err
+	}
+
+	if transaction != nil {
+		if c.addInboundPending() {
+			go c.completeInboundTransaction(msg, message, transaction, wg)
+		} else {
+			c.endInboundTransaction(msg.IEEEAddress, message, transaction)
 		}
 	}
 
@@ -93,7 +173,30 @@ func (c *communicator) UnregisterMatch(match Match) {
 	delete(c.matches, match.id)
 }
 
+// Request sends the message without waiting for a reply, using the transaction sequence provided. Unless the message
+// is itself a response, the sequence is reserved while it is sent if it is not already in flight, so it is not
+// allocated automatically to another request.
 func (c *communicator) Request(ctx context.Context, address zigbee.IEEEAddress, requireAck bool, message zcl.Message) error {
+	identifier, err := c.commandIdentifier(message)
+
+	if err != nil {
+		return fmt.Errorf("ZCL communicator failed to send message during marshalling: %w", err)
+	}
+
+	if !c.isResponse(message, identifier) && c.sequences.reserve(address, message.TransactionSequence) {
+		defer c.sequences.release(address, message.TransactionSequence)
+	}
+
+	return retry(ctx, c.retryPolicy(ctx), func(ctx context.Context) error {
+		return c.send(ctx, address, requireAck, message)
+	})
+}
+
+func (c *communicator) send(ctx context.Context, address zigbee.IEEEAddress, requireAck bool, message zcl.Message) error {
+	if c.automaticDefaultResponse {
+		c.markInboundTransactionResponded(address, message)
+	}
+
 	appMessage, err := c.CommandRegistry.Marshal(message)
 
 	if err != nil {
@@ -103,33 +206,115 @@ func (c *communicator) Request(ctx context.Context, address zigbee.IEEEAddress,
 	err = c.Provider.SendApplicationMessageToNode(ctx, address, appMessage, requireAck)
 
 	if err != nil {
-		return fmt.Errorf("ZCL communicator failed to send via provider: %w", err)
+		return &SendError{Err: err}
 	}
 
 	return nil
 }
 
+// RequestResponse sends the message and waits for its response. If the Default Response is disabled and the command
+// has no specific response the node will not reply, so an empty message is returned once it has been sent.
 func (c *communicator) RequestResponse(ctx context.Context, address zigbee.IEEEAddress, requireAck bool, message zcl.Message) (zcl.Message, error) {
+	if c.automaticTransactionSequence {
+		sequence, err := c.sequences.allocate(address)
+
+		if err != nil {
+			return zcl.Message{}, err
+		}
+
+		defer c.sequences.release(address, sequence)
+		message.TransactionSequence = sequence
+	} else if c.sequences.reserve(address, message.TransactionSequence) {
+		defer c.sequences.release(address, message.TransactionSequence)
+	} else {
+		return zcl.Message{}, ErrTransactionSequenceInUse
+	}
+
+	identifier, err := c.commandIdentifier(message)
+
+	if err != nil {
+		return zcl.Message{}, fmt.Errorf("ZCL communicator failed to send message during marshalling: %w", err)
+	}
+
+	if message.DisableDefaultResponse && !c.expectsResponse(message, identifier) {
+		err = retry(ctx, c.retryPolicy(ctx), func(ctx context.Context) error {
+			return c.send(ctx, address, requireAck, message)
+		})
+
+		return zcl.Message{}, err
+	}
+
 	ch := make(chan zcl.Message, 1)
 
-	match := NewMatch(AddressAndSequenceMatch(address, message.TransactionSequence),
+	match := NewMatch(ResponseMatch(address, message, identifier),
 		func(recvMessage MessageWithSource) {
-			ch <- recvMessage.Message
+			select {
+			case ch <- recvMessage.Message:
+			default:
+			}
 		})
 
+	match.inline = true
+
 	c.RegisterMatch(match)
 	defer c.UnregisterMatch(match)
 
-	if err := c.Request(ctx, address, requireAck, message); err != nil {
+	var response zcl.Message
+
+	err = retry(ctx, c.retryPolicy(ctx), func(ctx context.Context) error {
+		select {
+		case response = <-ch:
+			return defaultResponseError(response, identifier)
+		default:
+		}
+
+		if err := c.send(ctx, address, requireAck, message); err != nil {
+			return err
+		}
+
+		select {
+		case response = <-ch:
+			return defaultResponseError(response, identifier)
+		case <-ctx.Done():
+			return ErrNoResponse
+		}
+	})
+
+	if err != nil {
 		return zcl.Message{}, err
 	}
 
-	select {
-	case resp := <-ch:
-		return resp, nil
-	case <-ctx.Done():
-		return zcl.Message{}, errors.New("ZCL communicator waiting for reply, context expired")
+	return response, nil
+}
+
+// defaultResponseError returns a zcl.StatusError if the response is a Default Response to the command which reports a
+// failure, a successful Default Response is the completion of commands which have no specific response.
+func defaultResponseError(response zcl.Message, identifier zcl.CommandIdentifier) error {
+	if response.FrameType != zcl.FrameGlobal {
+		return nil
+	}
+
+	defaultResponse, is := response.Command.(*global.DefaultResponse)
+
+	if !is || zcl.CommandIdentifier(defaultResponse.CommandIdentifier) != identifier || defaultResponse.Status.IsSuccess() {
+		return nil
 	}
+
+	return zcl.NewStatusError(defaultResponse.Status, response.ClusterID, identifier)
+}
+
+func (c *communicator) commandIdentifier(message zcl.Message) (zcl.CommandIdentifier, error) {
+	switch message.Command.(type) {
+	case zcl.UnknownCommand, *zcl.UnknownCommand:
+		return message.CommandIdentifier, nil
+	}
+
+	if message.FrameType == zcl.FrameGlobal {
+		return c.CommandRegistry.GetGlobalCommandIdentifier(message.Command)
+	}
+
+	identifier, _, err := c.CommandRegistry.FindLocalCommandIdentifier(message.ClusterID, message.Manufacturer, message.Direction, message.Command)
+	return identifier, err
 }
 
 func (c *communicator) ReadAttributes(ctx context.Context, ieeeAddress zigbee.IEEEAddress, requireAck bool, cluster zigbee.ClusterID, code zigbee.ManufacturerCode, sourceEndpoint zigbee.Endpoint, destEndpoint zigbee.Endpoint, transactionSequence uint8, attributes []zcl.AttributeID) ([]global.ReadAttributeResponseRecord, error) {
@@ -173,6 +358,14 @@ func (c *communicator) WriteAttributes(ctx context.Context, ieeeAddress zigbee.I
 	var records []global.WriteAttributesRecord
 
 	for k, v := range attributes {
+		v := v
+
+		if definition, found := c.CommandRegistry.Attribute(cluster, code, k); found {
+			if err := definition.ValidateWrite(v); err != nil {
+				return nil, err
+			}
+		}
+
 		records = append(records, global.WriteAttributesRecord{
 			Identifier:    k,
 			DataTypeValue: &v,
@@ -215,6 +408,16 @@ func WriteResponsesToMap(recs []global.WriteAttributesResponseRecord) map[zcl.At
 }
 
 func (c *communicator) ConfigureReporting(ctx context.Context, ieeeAddress zigbee.IEEEAddress, requireAck bool, cluster zigbee.ClusterID, code zigbee.ManufacturerCode, sourceEndpoint zigbee.Endpoint, destEndpoint zigbee.Endpoint, transactionSequence uint8, attributeId zcl.AttributeID, dataType zcl.AttributeDataType, minimumReportingInterval uint16, maximumReportingInterval uint16, reportableChange interface{}) error {
+	if definition, found := c.CommandRegistry.Attribute(cluster, code, attributeId); found {
+		change, err := definition.ValidateReporting(dataType, reportableChange)
+
+		if err != nil {
+			return err
+		}
+
+		reportableChange = change
+	}
+
 	request := zcl.Message{
 		FrameType:           zcl.FrameGlobal,
 		Direction:           zcl.ClientToServer,
@@ -253,8 +456,8 @@ func (c *communicator) ConfigureReporting(ctx context.Context, ieeeAddress zigbe
 			return errors.New("incorrect attribute id response sent to configure reporting")
 		}
 
-		if readResponse.Records[0].Status != 0 {
-			return errors.New("non success response sent to configure reporting")
+		if !readResponse.Records[0].Status.IsSuccess() {
+			return zcl.NewAttributeStatusError(readResponse.Records[0].Status, cluster, global.ConfigureReportingID, attributeId)
 		}
 
 		return nil
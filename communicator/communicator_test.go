@@ -2,6 +2,7 @@ package communicator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/shimmeringbee/zcl"
 	"github.com/shimmeringbee/zcl/commands/global"
@@ -43,6 +44,39 @@ func TestCommunicator_ProcessIncomingMessage(t *testing.T) {
 		assert.Error(t, err)
 		fmt.Println(err)
 	})
+
+	t.Run("an unrecognised message is delivered as an unknown command when unmarshal options request it", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+
+		c := NewCommunicator(provider, cr, WithUnmarshalOptions(zcl.PreserveUnknownCommands()))
+
+		received := make(chan zcl.Message, 1)
+
+		c.RegisterMatch(NewMatch(func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, message zcl.Message) bool {
+			return true
+		}, func(source MessageWithSource) {
+			received <- source.Message
+		}))
+
+		err := c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+			IncomingMessage: zigbee.IncomingMessage{
+				ApplicationMessage: zigbee.ApplicationMessage{
+					ClusterID: 0xef00,
+					Data:      []byte{0b00000001, 0x10, 0x02, 0xaa},
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		select {
+		case message := <-received:
+			assert.Equal(t, zcl.CommandIdentifier(0x02), message.CommandIdentifier)
+			assert.Equal(t, &zcl.UnknownCommand{Payload: []byte{0xaa}}, message.Command)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("message was not delivered")
+		}
+	})
 }
 
 func TestCommunicator_Request(t *testing.T) {
@@ -513,6 +547,76 @@ func TestCommunicator_GlobalWritesAttributes(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedResponse.Records, resp)
 	})
+
+	t.Run("writes each attribute with its own value", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(mockProvider, cr)
+
+		ieee := zigbee.IEEEAddress(0x0102030405060708)
+		clusterId := zigbee.ClusterID(0x1223)
+
+		var records []global.WriteAttributesRecord
+
+		mockProvider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			request, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+			records = request.Command.(*global.WriteAttributes).Records
+
+			appMessageReply, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: request.TransactionSequence,
+				ClusterID:           clusterId,
+				SourceEndpoint:      2,
+				DestinationEndpoint: 1,
+				Command:             &global.WriteAttributesResponse{Records: []global.WriteAttributesResponseRecord{{Status: 0}}},
+			})
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: ieee},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessageReply},
+			})
+		})
+
+		_, err := c.WriteAttributes(context.Background(), ieee, false, clusterId, zigbee.NoManufacturer, 1, 2, 0x01, map[zcl.AttributeID]zcl.AttributeDataTypeValue{
+			0x0001: {DataType: zcl.TypeUnsignedInt8, Value: uint8(1)},
+			0x0002: {DataType: zcl.TypeUnsignedInt8, Value: uint8(2)},
+		})
+		assert.NoError(t, err)
+
+		assert.ElementsMatch(t, []global.WriteAttributesRecord{
+			{Identifier: 0x0001, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeUnsignedInt8, Value: uint64(1)}},
+			{Identifier: 0x0002, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeUnsignedInt8, Value: uint64(2)}},
+		}, records)
+	})
+
+	t.Run("rejects writes which do not match a registered attribute definition without sending", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		clusterId := zigbee.ClusterID(0x1223)
+
+		cr.MustRegisterAttributes(clusterId, zigbee.NoManufacturer,
+			zcl.AttributeDefinition{ID: 0x0001, Name: "ReadOnly", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+			zcl.AttributeDefinition{ID: 0x0002, Name: "Limited", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadWrite, Maximum: 10},
+		)
+
+		c := NewCommunicator(mockProvider, cr)
+
+		_, err := c.WriteAttributes(context.Background(), zigbee.IEEEAddress(0x0102030405060708), true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, map[zcl.AttributeID]zcl.AttributeDataTypeValue{0x0001: {DataType: zcl.TypeUnsignedInt8, Value: uint8(1)}})
+		assert.True(t, errors.Is(err, zcl.ErrAttributeNotWritable))
+
+		_, err = c.WriteAttributes(context.Background(), zigbee.IEEEAddress(0x0102030405060708), true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, map[zcl.AttributeID]zcl.AttributeDataTypeValue{0x0002: {DataType: zcl.TypeUnsignedInt16, Value: uint16(1)}})
+		assert.True(t, errors.Is(err, zcl.ErrAttributeDataTypeMismatch))
+
+		_, err = c.WriteAttributes(context.Background(), zigbee.IEEEAddress(0x0102030405060708), true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, map[zcl.AttributeID]zcl.AttributeDataTypeValue{0x0002: {DataType: zcl.TypeUnsignedInt8, Value: uint8(11)}})
+		assert.True(t, errors.Is(err, zcl.ErrValueOutOfRange))
+
+		mockProvider.AssertNotCalled(t, "SendApplicationMessageToNode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestCommunicator_GlobalConfigureReporting(t *testing.T) {
@@ -615,4 +719,257 @@ func TestCommunicator_GlobalConfigureReporting(t *testing.T) {
 		err := c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, srcEndpoint, destEndpoint, transactionSequence, attributeId, dataType, minInterval, maxInterval, reportableChange)
 		assert.NoError(t, err)
 	})
+	t.Run("a non success status from the device is returned as a status error", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		ieee := zigbee.IEEEAddress(0x0102030405060708)
+
+		c := NewCommunicator(mockProvider, cr)
+
+		clusterId := zigbee.ClusterID(0x1223)
+		attributeId := zcl.AttributeID(0x0001)
+
+		mockProvider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, true).Return(nil).Run(func(args mock.Arguments) {
+			message := zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: 0x7f,
+				ClusterID:           clusterId,
+				SourceEndpoint:      8,
+				DestinationEndpoint: 4,
+				Command: &global.ConfigureReportingResponse{
+					Records: []global.ConfigureReportingResponseRecord{
+						{
+							Status:     zcl.StatusUnreportableAttribute,
+							Identifier: attributeId,
+						},
+					},
+				},
+			}
+
+			appMessageReply, _ := cr.Marshal(message)
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: ieee},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessageReply},
+			})
+		})
+
+		err := c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, 4, 8, 0x7f, attributeId, zcl.TypeUnsignedInt8, 0, 60, uint64(1))
+
+		var statusErr *zcl.StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, zcl.StatusUnreportableAttribute, statusErr.Status)
+		assert.Equal(t, clusterId, statusErr.ClusterID)
+		assert.Equal(t, attributeId, statusErr.AttributeID)
+	})
+
+	t.Run("rejects reporting configuration which does not match a registered attribute definition without sending", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		clusterId := zigbee.ClusterID(0x1223)
+
+		cr.MustRegisterAttributes(clusterId, zigbee.NoManufacturer,
+			zcl.AttributeDefinition{ID: 0x0001, Name: "NotReportable", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessRead},
+			zcl.AttributeDefinition{ID: 0x0002, Name: "Reportable", DataType: zcl.TypeUnsignedInt8, Access: zcl.AccessReadReport},
+		)
+
+		c := NewCommunicator(mockProvider, cr)
+		ieee := zigbee.IEEEAddress(0x0102030405060708)
+
+		err := c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, 0x0001, zcl.TypeUnsignedInt8, 0, 60, uint64(1))
+		assert.True(t, errors.Is(err, zcl.ErrAttributeNotReportable))
+
+		err = c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, 0x0002, zcl.TypeUnsignedInt16, 0, 60, uint64(1))
+		assert.True(t, errors.Is(err, zcl.ErrAttributeDataTypeMismatch))
+
+		err = c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, 0x0002, zcl.TypeUnsignedInt8, 0, 60, 0x100)
+		assert.True(t, errors.Is(err, zcl.ErrValueOutOfRange))
+
+		err = c.ConfigureReporting(context.Background(), ieee, true, clusterId, zigbee.NoManufacturer, 1, 1, 0x01, 0x0002, zcl.TypeUnsignedInt8, 0, 60, "a")
+		assert.True(t, errors.Is(err, zcl.ErrIncompatibleValue))
+
+		mockProvider.AssertNotCalled(t, "SendApplicationMessageToNode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommunicator_DefaultResponseStatus(t *testing.T) {
+	type LocalCommand struct{}
+
+	ieee := zigbee.IEEEAddress(2)
+	clusterId := zigbee.ClusterID(0x0006)
+
+	setup := func(status zcl.Status, commandIdentifier zcl.CommandIdentifier) (*zigbee.MockProvider, Communicator) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+		cr.RegisterLocal(clusterId, zigbee.NoManufacturer, zcl.ClientToServer, 0x01, &LocalCommand{})
+
+		c := NewCommunicator(mockProvider, cr)
+
+		mockProvider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			request, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+
+			appMessageReply, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: request.TransactionSequence,
+				ClusterID:           clusterId,
+				SourceEndpoint:      request.DestinationEndpoint,
+				DestinationEndpoint: request.SourceEndpoint,
+				Command: &global.DefaultResponse{
+					CommandIdentifier: uint8(commandIdentifier),
+					Status:            status,
+				},
+			})
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: ieee},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessageReply},
+			})
+		})
+
+		return mockProvider, c
+	}
+
+	t.Run("a failing default response to a read is returned as a status error", func(t *testing.T) {
+		mockProvider, c := setup(zcl.StatusUnsupportedGeneralCommand, global.ReadAttributesID)
+
+		_, err := c.ReadAttributes(context.Background(), ieee, false, clusterId, zigbee.NoManufacturer, 1, 2, 0x10, []zcl.AttributeID{0})
+
+		var statusErr *zcl.StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, zcl.StatusUnsupportedGeneralCommand, statusErr.Status)
+		assert.Equal(t, clusterId, statusErr.ClusterID)
+		assert.Equal(t, global.ReadAttributesID, statusErr.CommandIdentifier)
+
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("a failing default response to a local command is returned as a status error", func(t *testing.T) {
+		mockProvider, c := setup(zcl.StatusUnsupportedClusterCommand, 0x01)
+
+		_, err := c.RequestResponse(context.Background(), ieee, false, zcl.Message{
+			FrameType:           zcl.FrameLocal,
+			Direction:           zcl.ClientToServer,
+			TransactionSequence: 0x11,
+			ClusterID:           clusterId,
+			SourceEndpoint:      1,
+			DestinationEndpoint: 2,
+			Command:             &LocalCommand{},
+		})
+
+		var statusErr *zcl.StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, zcl.StatusUnsupportedClusterCommand, statusErr.Status)
+		assert.Equal(t, zcl.CommandIdentifier(0x01), statusErr.CommandIdentifier)
+
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("a successful default response completes a local command", func(t *testing.T) {
+		mockProvider, c := setup(zcl.StatusSuccess, 0x01)
+
+		response, err := c.RequestResponse(context.Background(), ieee, false, zcl.Message{
+			FrameType:           zcl.FrameLocal,
+			Direction:           zcl.ClientToServer,
+			TransactionSequence: 0x12,
+			ClusterID:           clusterId,
+			SourceEndpoint:      1,
+			DestinationEndpoint: 2,
+			Command:             &LocalCommand{},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &global.DefaultResponse{CommandIdentifier: 0x01, Status: zcl.StatusSuccess}, response.Command)
+
+		mockProvider.AssertExpectations(t)
+	})
+}
+
+func TestCommunicator_DisableDefaultResponse(t *testing.T) {
+	type LocalCommand struct{}
+	type LocalCommandResponse struct{}
+
+	ieee := zigbee.IEEEAddress(2)
+	clusterId := zigbee.ClusterID(0x0006)
+
+	t.Run("a command without a specific response returns once sent if the default response is disabled", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+		cr.RegisterLocal(clusterId, zigbee.NoManufacturer, zcl.ClientToServer, 0x01, &LocalCommand{})
+
+		c := NewCommunicator(mockProvider, cr)
+
+		mockProvider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		response, err := c.RequestResponse(ctx, ieee, false, zcl.Message{
+			FrameType:              zcl.FrameLocal,
+			Direction:              zcl.ClientToServer,
+			DisableDefaultResponse: true,
+			ClusterID:              clusterId,
+			SourceEndpoint:         1,
+			DestinationEndpoint:    2,
+			Command:                &LocalCommand{},
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, response.Command)
+		assert.NoError(t, ctx.Err())
+
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("a command with a specific response waits for it if the default response is disabled", func(t *testing.T) {
+		mockProvider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+		cr.RegisterLocal(clusterId, zigbee.NoManufacturer, zcl.ClientToServer, 0x01, &LocalCommand{})
+		cr.RegisterLocal(clusterId, zigbee.NoManufacturer, zcl.ServerToClient, 0x01, &LocalCommandResponse{})
+		cr.RegisterLocalResponse(clusterId, zigbee.NoManufacturer, zcl.ClientToServer, 0x01, 0x01)
+
+		c := NewCommunicator(mockProvider, cr)
+
+		mockProvider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			request, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+
+			appMessageReply, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameLocal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: request.TransactionSequence,
+				ClusterID:           clusterId,
+				SourceEndpoint:      request.DestinationEndpoint,
+				DestinationEndpoint: request.SourceEndpoint,
+				Command:             &LocalCommandResponse{},
+			})
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: ieee},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessageReply},
+			})
+		}).Once()
+
+		response, err := c.RequestResponse(context.Background(), ieee, false, zcl.Message{
+			FrameType:              zcl.FrameLocal,
+			Direction:              zcl.ClientToServer,
+			DisableDefaultResponse: true,
+			ClusterID:              clusterId,
+			SourceEndpoint:         1,
+			DestinationEndpoint:    2,
+			Command:                &LocalCommand{},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &LocalCommandResponse{}, response.Command)
+
+		mockProvider.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,199 @@
+package communicator
+
+import (
+	"context"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+ * A Default Response is only generated for unicast commands which are not themselves a Default Response, and if the
+ * sender set Disable Default Response then only when the command failed. As per 2.5.12.2 in ZCL Revision 8.
+ */
+func defaultResponseRequired(msg zigbee.NodeIncomingMessageEvent, message zcl.Message, success bool) bool {
+	if msg.Broadcast || msg.GroupID != 0 {
+		return false
+	}
+
+	if message.FrameType == zcl.FrameGlobal && message.CommandIdentifier == global.DefaultResponseID {
+		return false
+	}
+
+	if message.DisableDefaultResponse && success {
+		return false
+	}
+
+	return true
+}
+
+const defaultResponseTimeout = 5 * time.Second
+
+type inboundKey struct {
+	address   zigbee.IEEEAddress
+	sequence  uint8
+	clusterID zigbee.ClusterID
+	direction zcl.Direction
+}
+
+type inboundTransaction struct {
+	mutex     sync.Mutex
+	responded bool
+	status    zcl.Status
+}
+
+func (t *inboundTransaction) setStatus(status zcl.Status) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.status = status
+}
+
+func (t *inboundTransaction) markResponded() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.responded = true
+}
+
+func (t *inboundTransaction) outcome() (bool, zcl.Status) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.responded, t.status
+}
+
+func (c *communicator) beginInboundTransaction(address zigbee.IEEEAddress, message zcl.Message) *inboundTransaction {
+	c.inboundMutex.Lock()
+	defer c.inboundMutex.Unlock()
+
+	transaction := &inboundTransaction{}
+	c.inbound[inboundKey{address: address, sequence: message.TransactionSequence, clusterID: message.ClusterID, direction: message.Direction}] = transaction
+
+	return transaction
+}
+
+// markInboundTransactionResponded records that a response has been sent to an inbound transaction, other commands
+// sent with the same transaction sequence do not prevent the Default Response.
+func (c *communicator) markInboundTransactionResponded(address zigbee.IEEEAddress, message zcl.Message) {
+	if identifier, err := c.commandIdentifier(message); err != nil || !c.isResponse(message, identifier) {
+		return
+	}
+
+	c.inboundMutex.Lock()
+	defer c.inboundMutex.Unlock()
+
+	key := inboundKey{address: address, sequence: message.TransactionSequence, clusterID: message.ClusterID, direction: oppositeDirection(message.Direction)}
+
+	if transaction, found := c.inbound[key]; found {
+		transaction.markResponded()
+	}
+}
+
+func (c *communicator) endInboundTransaction(address zigbee.IEEEAddress, message zcl.Message, transaction *inboundTransaction) {
+	c.inboundMutex.Lock()
+	defer c.inboundMutex.Unlock()
+
+	key := inboundKey{address: address, sequence: message.TransactionSequence, clusterID: message.ClusterID, direction: message.Direction}
+
+	if c.inbound[key] == transaction {
+		delete(c.inbound, key)
+	}
+}
+
+func (c *communicator) completeInboundTransaction(msg zigbee.NodeIncomingMessageEvent, message zcl.Message, transaction *inboundTransaction, wg *sync.WaitGroup) {
+	defer c.inboundPending.Done()
+
+	wg.Wait()
+	c.endInboundTransaction(msg.IEEEAddress, message, transaction)
+
+	responded, status := transaction.outcome()
+
+	if responded || !defaultResponseRequired(msg, message, status.IsSuccess()) {
+		return
+	}
+
+	response := zcl.Message{
+		FrameType:              zcl.FrameGlobal,
+		Direction:              oppositeDirection(message.Direction),
+		DisableDefaultResponse: true,
+		TransactionSequence:    message.TransactionSequence,
+		Manufacturer:           message.Manufacturer,
+		ClusterID:              message.ClusterID,
+		SourceEndpoint:         message.DestinationEndpoint,
+		DestinationEndpoint:    message.SourceEndpoint,
+		Command: &global.DefaultResponse{
+			CommandIdentifier: uint8(message.CommandIdentifier),
+			Status:            status,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultResponseTimeout)
+	defer cancel()
+
+	_ = c.Request(ctx, msg.IEEEAddress, false, response)
+}
+
+// rejectUnknownCommand replies with an unsupported command Default Response if the message could not be unmarshalled
+// because its command is not registered.
+func (c *communicator) rejectUnknownCommand(msg zigbee.NodeIncomingMessageEvent) {
+	options := append([]zcl.UnmarshalOption{}, c.unmarshalOptions...)
+	message, err := c.CommandRegistry.Unmarshal(msg.ApplicationMessage, append(options, zcl.PreserveUnknownCommands())...)
+
+	if err != nil {
+		return
+	}
+
+	if _, isUnknown := message.Command.(*zcl.UnknownCommand); !isUnknown {
+		return
+	}
+
+	if !c.addInboundPending() {
+		return
+	}
+
+	transaction := c.beginInboundTransaction(msg.IEEEAddress, message)
+	transaction.setStatus(unsupportedCommandStatus(message))
+
+	go c.completeInboundTransaction(msg, message, transaction, &sync.WaitGroup{})
+}
+
+// addInboundPending records an inbound transaction which will be completed in the background, unless the communicator
+// has been stopped, so that Stop can wait for it.
+func (c *communicator) addInboundPending() bool {
+	c.stopMutex.RLock()
+	defer c.stopMutex.RUnlock()
+
+	if atomic.LoadUint32(&c.stopped) != 0 {
+		return false
+	}
+
+	c.inboundPending.Add(1)
+	return true
+}
+
+func unsupportedCommandStatus(message zcl.Message) zcl.Status {
+	manufacturerSpecific := message.Manufacturer != zigbee.NoManufacturer
+
+	switch {
+	case message.FrameType == zcl.FrameGlobal && manufacturerSpecific:
+		return zcl.StatusUnsupportedManufacturerGeneralCommand
+	case message.FrameType == zcl.FrameGlobal:
+		return zcl.StatusUnsupportedGeneralCommand
+	case manufacturerSpecific:
+		return zcl.StatusUnsupportedManufacturerClusterCommand
+	default:
+		return zcl.StatusUnsupportedClusterCommand
+	}
+}
+
+func oppositeDirection(direction zcl.Direction) zcl.Direction {
+	if direction == zcl.ClientToServer {
+		return zcl.ServerToClient
+	}
+
+	return zcl.ClientToServer
+}
@@ -0,0 +1,362 @@
+package communicator
+
+import (
+	"context"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zcl/commands/local/onoff"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func Test_defaultResponseRequired(t *testing.T) {
+	unicast := zigbee.NodeIncomingMessageEvent{}
+
+	t.Run("a unicast command with disable default response clear requires a response", func(t *testing.T) {
+		message := zcl.Message{FrameType: zcl.FrameLocal, CommandIdentifier: 0x01}
+
+		assert.True(t, defaultResponseRequired(unicast, message, true))
+		assert.True(t, defaultResponseRequired(unicast, message, false))
+	})
+
+	t.Run("a unicast command with disable default response set only requires a response on failure", func(t *testing.T) {
+		message := zcl.Message{FrameType: zcl.FrameLocal, CommandIdentifier: 0x01, DisableDefaultResponse: true}
+
+		assert.False(t, defaultResponseRequired(unicast, message, true))
+		assert.True(t, defaultResponseRequired(unicast, message, false))
+	})
+
+	t.Run("a default response never requires a response", func(t *testing.T) {
+		message := zcl.Message{FrameType: zcl.FrameGlobal, CommandIdentifier: global.DefaultResponseID}
+
+		assert.False(t, defaultResponseRequired(unicast, message, false))
+	})
+
+	t.Run("broadcast and group commands never require a response", func(t *testing.T) {
+		message := zcl.Message{FrameType: zcl.FrameLocal, CommandIdentifier: 0x01}
+
+		broadcast := zigbee.NodeIncomingMessageEvent{IncomingMessage: zigbee.IncomingMessage{Broadcast: true}}
+		assert.False(t, defaultResponseRequired(broadcast, message, false))
+
+		group := zigbee.NodeIncomingMessageEvent{IncomingMessage: zigbee.IncomingMessage{GroupID: 0x1000}}
+		assert.False(t, defaultResponseRequired(group, message, false))
+	})
+}
+
+func TestCommunicator_AutomaticDefaultResponse(t *testing.T) {
+	ieee := zigbee.IEEEAddress(0x0102030405060708)
+
+	toggle := zcl.Message{
+		FrameType:           zcl.FrameLocal,
+		Direction:           zcl.ClientToServer,
+		TransactionSequence: 0x20,
+		Manufacturer:        zigbee.NoManufacturer,
+		ClusterID:           zcl.OnOffId,
+		SourceEndpoint:      0x02,
+		DestinationEndpoint: 0x01,
+		Command:             &onoff.Toggle{},
+	}
+
+	defaultResponse := func(status zcl.Status) zcl.Message {
+		return zcl.Message{
+			FrameType:              zcl.FrameGlobal,
+			Direction:              zcl.ServerToClient,
+			DisableDefaultResponse: true,
+			TransactionSequence:    0x20,
+			Manufacturer:           zigbee.NoManufacturer,
+			ClusterID:              zcl.OnOffId,
+			SourceEndpoint:         0x01,
+			DestinationEndpoint:    0x02,
+			Command: &global.DefaultResponse{
+				CommandIdentifier: uint8(onoff.ToggleId),
+				Status:            status,
+			},
+		}
+	}
+
+	setup := func() (*zigbee.MockProvider, *zcl.CommandRegistry, Communicator, zigbee.NodeIncomingMessageEvent) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+		onoff.Register(cr)
+
+		c := NewCommunicator(provider, cr, WithAutomaticDefaultResponse())
+
+		appMessage, err := cr.Marshal(toggle)
+		assert.NoError(t, err)
+
+		event := zigbee.NodeIncomingMessageEvent{
+			Node:            zigbee.Node{IEEEAddress: ieee},
+			IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+		}
+
+		return provider, cr, c, event
+	}
+
+	t.Run("an unanswered command results in a success default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		expectedAppMessage, _ := cr.Marshal(defaultResponse(zcl.StatusSuccess))
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		})
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a status supplied by a callback is used in the default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		expectedAppMessage, _ := cr.Marshal(defaultResponse(zcl.StatusUnsupportedClusterCommand))
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		})
+
+		c.RegisterMatch(NewMatch(func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+			return true
+		}, func(source MessageWithSource) {
+			source.SetDefaultResponseStatus(zcl.StatusUnsupportedClusterCommand)
+		}))
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a command answered by a callback with the same transaction sequence does not receive a default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		reply := defaultResponse(zcl.StatusSuccess)
+		reply.Command = &global.ReadAttributesResponse{}
+
+		expectedAppMessage, _ := cr.Marshal(reply)
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		}).Once()
+
+		c.RegisterMatch(NewMatch(func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+			return true
+		}, func(source MessageWithSource) {
+			_ = c.Request(context.Background(), source.SourceAddress, false, reply)
+		}))
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+		c.(*communicator).inboundPending.Wait()
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a command answered by a callback with a registered local response does not receive a default response", func(t *testing.T) {
+		type ToggleAnswer struct{}
+
+		provider, cr, c, event := setup()
+		cr.RegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ServerToClient, 0x50, &ToggleAnswer{})
+		cr.RegisterLocalResponse(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, onoff.ToggleId, 0x50)
+
+		reply := defaultResponse(zcl.StatusSuccess)
+		reply.FrameType = zcl.FrameLocal
+		reply.Command = &ToggleAnswer{}
+
+		expectedAppMessage, _ := cr.Marshal(reply)
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		}).Once()
+
+		c.RegisterMatch(NewMatch(func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+			return true
+		}, func(source MessageWithSource) {
+			_ = c.Request(context.Background(), source.SourceAddress, false, reply)
+		}))
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+		c.(*communicator).inboundPending.Wait()
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a command sent by a callback with the same transaction sequence which is not a response does not prevent the default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		request := defaultResponse(zcl.StatusSuccess)
+		request.Command = &global.ReadAttributes{Identifier: []zcl.AttributeID{0}}
+
+		requestAppMessage, _ := cr.Marshal(request)
+		expectedAppMessage, _ := cr.Marshal(defaultResponse(zcl.StatusSuccess))
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, requestAppMessage, false).Return(nil).Once()
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		}).Once()
+
+		c.RegisterMatch(NewMatch(func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+			return zclMessage.FrameType == zcl.FrameLocal
+		}, func(source MessageWithSource) {
+			_ = c.Request(context.Background(), source.SourceAddress, false, request)
+		}))
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a command with disable default response set does not receive a success default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		message := toggle
+		message.DisableDefaultResponse = true
+		event.ApplicationMessage, _ = cr.Marshal(message)
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		c.(*communicator).inboundPending.Wait()
+
+		provider.AssertNotCalled(t, "SendApplicationMessageToNode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a command which is not registered receives an unsupported cluster command default response", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		type UnregisteredCommand struct{}
+
+		senderRegistry := zcl.NewCommandRegistry()
+		onoff.Register(senderRegistry)
+		senderRegistry.MustRegisterLocal(zcl.OnOffId, zigbee.NoManufacturer, zcl.ClientToServer, 0x50, &UnregisteredCommand{})
+
+		message := toggle
+		message.Command = &UnregisteredCommand{}
+		event.ApplicationMessage, _ = senderRegistry.Marshal(message)
+
+		expected := defaultResponse(zcl.StatusUnsupportedClusterCommand)
+		expected.Command.(*global.DefaultResponse).CommandIdentifier = 0x50
+		expectedAppMessage, _ := cr.Marshal(expected)
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		}).Once()
+
+		err := c.ProcessIncomingMessage(event)
+		assert.Error(t, err)
+
+		waitForSend(t, sent)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a preserved unknown command without a callback receives an unsupported general command default response", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr, WithAutomaticDefaultResponse(), WithUnmarshalOptions(zcl.PreserveUnknownCommands()))
+
+		message := toggle
+		message.FrameType = zcl.FrameGlobal
+		message.CommandIdentifier = 0x40
+		message.Command = &zcl.UnknownCommand{}
+		appMessage, _ := cr.Marshal(message)
+
+		expected := defaultResponse(zcl.StatusUnsupportedGeneralCommand)
+		expected.Command.(*global.DefaultResponse).CommandIdentifier = 0x40
+		expectedAppMessage, _ := cr.Marshal(expected)
+		sent := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sent)
+		}).Once()
+
+		err := c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+			Node:            zigbee.Node{IEEEAddress: ieee},
+			IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+		})
+		assert.NoError(t, err)
+
+		waitForSend(t, sent)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("stop waits for pending default responses to be sent", func(t *testing.T) {
+		provider, cr, c, event := setup()
+
+		expectedAppMessage, _ := cr.Marshal(defaultResponse(zcl.StatusSuccess))
+		sending := make(chan struct{})
+		release := make(chan struct{})
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, expectedAppMessage, false).Return(nil).Run(func(args mock.Arguments) {
+			close(sending)
+			<-release
+		}).Once()
+
+		err := c.ProcessIncomingMessage(event)
+		assert.NoError(t, err)
+
+		waitForSend(t, sending)
+
+		stopped := make(chan struct{})
+
+		go func() {
+			c.(Dispatcher).Stop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			assert.Fail(t, "stop returned before the default response was sent")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		waitForSend(t, stopped)
+
+		err = c.ProcessIncomingMessage(event)
+		assert.ErrorIs(t, err, ErrCommunicatorStopped)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("unsupported command statuses depend on frame type and manufacturer", func(t *testing.T) {
+		assert.Equal(t, zcl.StatusUnsupportedClusterCommand, unsupportedCommandStatus(zcl.Message{FrameType: zcl.FrameLocal}))
+		assert.Equal(t, zcl.StatusUnsupportedGeneralCommand, unsupportedCommandStatus(zcl.Message{FrameType: zcl.FrameGlobal}))
+		assert.Equal(t, zcl.StatusUnsupportedManufacturerClusterCommand, unsupportedCommandStatus(zcl.Message{FrameType: zcl.FrameLocal, Manufacturer: 0x1234}))
+		assert.Equal(t, zcl.StatusUnsupportedManufacturerGeneralCommand, unsupportedCommandStatus(zcl.Message{FrameType: zcl.FrameGlobal, Manufacturer: 0x1234}))
+	})
+}
+
+func waitForSend(t *testing.T, sent chan struct{}) {
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		assert.Fail(t, "message was not sent")
+	}
+}
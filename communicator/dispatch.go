@@ -0,0 +1,228 @@
+package communicator
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zigbee"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrCommunicatorStopped = errors.New("ZCL communicator has been stopped")
+
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock causes ProcessIncomingMessage to block until the worker for the source has space in its queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop causes messages to be dropped if the worker for the source has a full queue, they are counted in
+	// DispatchStats. If automatic Default Responses are enabled the dropped message is replied to with a failure.
+	OverflowDrop
+)
+
+const defaultDispatchQueueSize = 64
+
+// DispatchConfig configures ordered dispatch of callbacks, messages are sharded across Workers by source address
+// so that callbacks for a node are called in the order its messages were received. Workers defaults to the number of
+// CPUs and QueueSize, the number of messages waiting for each worker, defaults to 64.
+type DispatchConfig struct {
+	Workers   int
+	QueueSize int
+	Overflow  OverflowPolicy
+}
+
+type DispatchStats struct {
+	Dispatched uint64
+	Dropped    uint64
+	Queued     int
+	Workers    int
+}
+
+// WithOrderedDispatch calls match callbacks from a bounded pool of workers, rather than a goroutine per match. As
+// callbacks for a node are called one at a time, a callback must not wait on another message from the same node.
+// Responses to RequestResponse are delivered outside of the pool, so requests may be made from callbacks.
+func WithOrderedDispatch(config DispatchConfig) Option {
+	return func(c *communicator) {
+		c.dispatcher = newDispatcher(config)
+	}
+}
+
+type dispatchJob struct {
+	source    MessageWithSource
+	callbacks []func(MessageWithSource)
+	wg        *sync.WaitGroup
+}
+
+type dispatcher struct {
+	dispatched uint64
+	dropped    uint64
+
+	overflow OverflowPolicy
+	queues   []chan dispatchJob
+
+	mutex   *sync.RWMutex
+	stopped bool
+	workers *sync.WaitGroup
+}
+
+func newDispatcher(config DispatchConfig) *dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultDispatchQueueSize
+	}
+
+	d := &dispatcher{
+		overflow: config.Overflow,
+		queues:   make([]chan dispatchJob, config.Workers),
+		mutex:    &sync.RWMutex{},
+		workers:  &sync.WaitGroup{},
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan dispatchJob, config.QueueSize)
+
+		d.workers.Add(1)
+		go d.worker(d.queues[i])
+	}
+
+	return d
+}
+
+func (d *dispatcher) worker(queue chan dispatchJob) {
+	defer d.workers.Done()
+
+	for job := range queue {
+		for _, callback := range job.callbacks {
+			callback(job.source)
+		}
+
+		job.wg.Done()
+	}
+}
+
+// dispatch queues the job on the worker for the address, returning false if it was dropped.
+func (d *dispatcher) dispatch(address zigbee.IEEEAddress, job dispatchJob) (bool, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.stopped {
+		return false, ErrCommunicatorStopped
+	}
+
+	queue := d.queues[uint64(address)%uint64(len(d.queues))]
+
+	if d.overflow == OverflowDrop {
+		select {
+		case queue <- job:
+		default:
+			atomic.AddUint64(&d.dropped, 1)
+			return false, nil
+		}
+	} else {
+		queue <- job
+	}
+
+	atomic.AddUint64(&d.dispatched, 1)
+	return true, nil
+}
+
+func (d *dispatcher) stats() DispatchStats {
+	queued := 0
+
+	for _, queue := range d.queues {
+		queued += len(queue)
+	}
+
+	return DispatchStats{
+		Dispatched: atomic.LoadUint64(&d.dispatched),
+		Dropped:    atomic.LoadUint64(&d.dropped),
+		Queued:     queued,
+		Workers:    len(d.queues),
+	}
+}
+
+// stop waits for queued callbacks to be called, and then stops the workers.
+func (d *dispatcher) stop() {
+	d.mutex.Lock()
+
+	if d.stopped {
+		d.mutex.Unlock()
+		return
+	}
+
+	d.stopped = true
+
+	for _, queue := range d.queues {
+		close(queue)
+	}
+
+	d.mutex.Unlock()
+	d.workers.Wait()
+}
+
+func (c *communicator) callbacks(source MessageWithSource, matches []Match) (*sync.WaitGroup, error) {
+	wg := &sync.WaitGroup{}
+
+	var pooled []func(MessageWithSource)
+
+	for _, match := range matches {
+		switch {
+		case match.inline:
+			match.callback(source)
+		case c.dispatcher != nil:
+			pooled = append(pooled, match.callback)
+		default:
+			wg.Add(1)
+
+			go func(match Match) {
+				defer wg.Done()
+				match.callback(source)
+			}(match)
+		}
+	}
+
+	if len(pooled) == 0 {
+		return wg, nil
+	}
+
+	wg.Add(1)
+	queued, err := c.dispatcher.dispatch(source.SourceAddress, dispatchJob{source: source, callbacks: pooled, wg: wg})
+
+	if !queued {
+		wg.Done()
+
+		if source.transaction != nil {
+			source.transaction.setStatus(zcl.StatusFailure)
+		}
+	}
+
+	return wg, err
+}
+
+// DispatchStats returns the counters of the ordered dispatch pool, it is empty if WithOrderedDispatch is not used.
+func (c *communicator) DispatchStats() DispatchStats {
+	if c.dispatcher == nil {
+		return DispatchStats{}
+	}
+
+	return c.dispatcher.stats()
+}
+
+// Stop causes further incoming messages to be rejected with ErrCommunicatorStopped, if WithOrderedDispatch is used it
+// then waits for any queued callbacks to complete and stops the workers. It returns once any automatic Default
+// Responses to messages already processed have been sent.
+func (c *communicator) Stop() {
+	c.stopMutex.Lock()
+	atomic.StoreUint32(&c.stopped, 1)
+	c.stopMutex.Unlock()
+
+	if c.dispatcher != nil {
+		c.dispatcher.stop()
+	}
+
+	c.inboundPending.Wait()
+}
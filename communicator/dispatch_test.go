@@ -0,0 +1,201 @@
+package communicator
+
+import (
+	"context"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCommunicator_OrderedDispatch(t *testing.T) {
+	report := func(cr *zcl.CommandRegistry, address zigbee.IEEEAddress, sequence uint8) zigbee.NodeIncomingMessageEvent {
+		appMessage, _ := cr.Marshal(zcl.Message{
+			FrameType:           zcl.FrameGlobal,
+			Direction:           zcl.ServerToClient,
+			TransactionSequence: sequence,
+			ClusterID:           0x0006,
+			SourceEndpoint:      1,
+			DestinationEndpoint: 1,
+			Command:             &global.ReportAttributes{Records: []global.ReportAttributesRecord{}},
+		})
+
+		return zigbee.NodeIncomingMessageEvent{
+			Node:            zigbee.Node{IEEEAddress: address},
+			IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+		}
+	}
+
+	setup := func(config DispatchConfig) (*zigbee.MockProvider, *zcl.CommandRegistry, *communicator) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		return provider, cr, NewCommunicator(provider, cr, WithOrderedDispatch(config)).(*communicator)
+	}
+
+	t.Run("callbacks for a node are called in the order messages are received", func(t *testing.T) {
+		_, cr, c := setup(DispatchConfig{Workers: 4, QueueSize: 256})
+		defer c.Stop()
+
+		mutex := &sync.Mutex{}
+		received := map[zigbee.IEEEAddress][]uint8{}
+
+		c.RegisterMatch(NewMatch(func(zigbee.IEEEAddress, zigbee.ApplicationMessage, zcl.Message) bool { return true }, func(source MessageWithSource) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			received[source.SourceAddress] = append(received[source.SourceAddress], source.Message.TransactionSequence)
+		}))
+
+		var expected []uint8
+
+		for i := 0; i < 100; i++ {
+			expected = append(expected, uint8(i))
+
+			for address := zigbee.IEEEAddress(1); address <= 3; address++ {
+				assert.NoError(t, c.ProcessIncomingMessage(report(cr, address, uint8(i))))
+			}
+		}
+
+		c.Stop()
+
+		for address := zigbee.IEEEAddress(1); address <= 3; address++ {
+			assert.Equal(t, expected, received[address])
+		}
+
+		stats := c.DispatchStats()
+		assert.Equal(t, uint64(300), stats.Dispatched)
+		assert.Equal(t, 4, stats.Workers)
+		assert.Equal(t, 0, stats.Queued)
+	})
+
+	t.Run("messages are dropped and counted when the queue is full", func(t *testing.T) {
+		_, cr, c := setup(DispatchConfig{Workers: 1, QueueSize: 1, Overflow: OverflowDrop})
+
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+
+		c.RegisterMatch(NewMatch(func(zigbee.IEEEAddress, zigbee.ApplicationMessage, zcl.Message) bool { return true }, func(source MessageWithSource) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+
+			<-release
+		}))
+
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 0)))
+		<-started
+
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 1)))
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 2)))
+
+		stats := c.DispatchStats()
+		assert.Equal(t, uint64(2), stats.Dispatched)
+		assert.Equal(t, uint64(1), stats.Dropped)
+		assert.Equal(t, 1, stats.Queued)
+
+		close(release)
+		c.Stop()
+	})
+
+	t.Run("processing blocks when the queue is full", func(t *testing.T) {
+		_, cr, c := setup(DispatchConfig{Workers: 1, QueueSize: 1})
+
+		release := make(chan struct{})
+
+		c.RegisterMatch(NewMatch(func(zigbee.IEEEAddress, zigbee.ApplicationMessage, zcl.Message) bool { return true }, func(source MessageWithSource) {
+			<-release
+		}))
+
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 0)))
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 1)))
+
+		done := make(chan struct{})
+
+		go func() {
+			assert.NoError(t, c.ProcessIncomingMessage(report(cr, 1, 2)))
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("processing did not block")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		<-done
+		c.Stop()
+
+		assert.Equal(t, uint64(3), c.DispatchStats().Dispatched)
+	})
+
+	t.Run("messages are rejected once stopped", func(t *testing.T) {
+		_, cr, c := setup(DispatchConfig{})
+
+		c.RegisterMatch(NewMatch(func(zigbee.IEEEAddress, zigbee.ApplicationMessage, zcl.Message) bool { return true }, func(source MessageWithSource) {}))
+		c.Stop()
+		c.Stop()
+
+		err := c.ProcessIncomingMessage(report(cr, 1, 0))
+		assert.ErrorIs(t, err, ErrCommunicatorStopped)
+	})
+
+	t.Run("messages are rejected once stopped without ordered dispatch, and receive no default response", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr, WithAutomaticDefaultResponse())
+		c.(Dispatcher).Stop()
+
+		err := c.ProcessIncomingMessage(report(cr, 1, 0))
+		assert.ErrorIs(t, err, ErrCommunicatorStopped)
+
+		c.(*communicator).inboundPending.Wait()
+		provider.AssertNotCalled(t, "SendApplicationMessageToNode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a callback can make a request to the node it is processing", func(t *testing.T) {
+		provider, cr, c := setup(DispatchConfig{Workers: 1})
+		defer c.Stop()
+
+		address := zigbee.IEEEAddress(1)
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, address, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			appMessage, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: 0x50,
+				ClusterID:           0x0006,
+				SourceEndpoint:      1,
+				DestinationEndpoint: 1,
+				Command:             &global.ReadAttributesResponse{Records: []global.ReadAttributeResponseRecord{}},
+			})
+
+			go c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: address},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+			})
+		})
+
+		result := make(chan error, 1)
+
+		c.RegisterMatch(NewMatch(CommandTypeMatch(&global.ReportAttributes{}), func(source MessageWithSource) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			_, err := c.ReadAttributes(ctx, address, false, 0x0006, zigbee.NoManufacturer, 1, 1, 0x50, []zcl.AttributeID{0})
+			result <- err
+		}))
+
+		assert.NoError(t, c.ProcessIncomingMessage(report(cr, address, 0)))
+		assert.NoError(t, <-result)
+	})
+}
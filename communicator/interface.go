@@ -20,3 +20,17 @@ type Communicator interface {
 	WriteAttributes(ctx context.Context, ieeeAddress zigbee.IEEEAddress, requireAck bool, cluster zigbee.ClusterID, code zigbee.ManufacturerCode, sourceEndpoint zigbee.Endpoint, destEndpoint zigbee.Endpoint, transactionSequence uint8, attributes map[zcl.AttributeID]zcl.AttributeDataTypeValue) ([]global.WriteAttributesResponseRecord, error)
 	ConfigureReporting(ctx context.Context, ieeeAddress zigbee.IEEEAddress, requireAck bool, cluster zigbee.ClusterID, code zigbee.ManufacturerCode, sourceEndpoint zigbee.Endpoint, destEndpoint zigbee.Endpoint, transactionSequence uint8, attributeId zcl.AttributeID, dataType zcl.AttributeDataType, minimumReportingInterval uint16, maximumReportingInterval uint16, reportableChange interface{}) error
 }
+
+// TransactionSequencer is implemented by the Communicator returned by NewCommunicator, it allows callers to hold a
+// transaction sequence which is not used by any other request made through the communicator.
+type TransactionSequencer interface {
+	AllocateTransactionSequence(address zigbee.IEEEAddress) (uint8, error)
+	ReleaseTransactionSequence(address zigbee.IEEEAddress, sequence uint8)
+}
+
+// Dispatcher is implemented by the Communicator returned by NewCommunicator, it allows incoming message processing to
+// be stopped and the ordered dispatch pool to be monitored.
+type Dispatcher interface {
+	DispatchStats() DispatchStats
+	Stop()
+}
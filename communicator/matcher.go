@@ -0,0 +1,185 @@
+package communicator
+
+import (
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"reflect"
+)
+
+func AddressMatch(matchAddress zigbee.IEEEAddress) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return matchAddress == address
+	}
+}
+
+func SequenceMatch(matchSequence uint8) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return matchSequence == zclMessage.TransactionSequence
+	}
+}
+
+func ClusterMatch(clusterID zigbee.ClusterID) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return clusterID == zclMessage.ClusterID
+	}
+}
+
+func SourceEndpointMatch(endpoint zigbee.Endpoint) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return endpoint == zclMessage.SourceEndpoint
+	}
+}
+
+func DirectionMatch(direction zcl.Direction) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return direction == zclMessage.Direction
+	}
+}
+
+func FrameTypeMatch(frameType zcl.FrameType) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return frameType == zclMessage.FrameType
+	}
+}
+
+// CommandMatch matches messages with the command identifier, global and local identifiers overlap so the frame type
+// must also be provided.
+func CommandMatch(frameType zcl.FrameType, identifier zcl.CommandIdentifier) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return frameType == zclMessage.FrameType && identifier == zclMessage.CommandIdentifier
+	}
+}
+
+// CommandTypeMatch matches messages whose command is the same Go type as the command provided, such as
+// &global.ReadAttributesResponse{}.
+func CommandTypeMatch(command interface{}) Matcher {
+	commandType := reflect.TypeOf(command)
+
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return commandType == reflect.TypeOf(zclMessage.Command)
+	}
+}
+
+// DefaultResponseMatch matches a Default Response sent in reply to the command identifier, of any status.
+func DefaultResponseMatch(identifier zcl.CommandIdentifier) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		if zclMessage.FrameType != zcl.FrameGlobal {
+			return false
+		}
+
+		defaultResponse, is := zclMessage.Command.(*global.DefaultResponse)
+
+		return is && zcl.CommandIdentifier(defaultResponse.CommandIdentifier) == identifier
+	}
+}
+
+func And(matchers ...Matcher) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		for _, matcher := range matchers {
+			if !matcher(address, appMsg, zclMessage) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func Or(matchers ...Matcher) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		for _, matcher := range matchers {
+			if matcher(address, appMsg, zclMessage) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func Not(matcher Matcher) Matcher {
+	return func(address zigbee.IEEEAddress, appMsg zigbee.ApplicationMessage, zclMessage zcl.Message) bool {
+		return !matcher(address, appMsg, zclMessage)
+	}
+}
+
+// broadcastEndpoint addresses every endpoint on a node, responses come from the endpoint which handled the request.
+const broadcastEndpoint = zigbee.Endpoint(0xff)
+
+var globalResponses = map[zcl.CommandIdentifier]zcl.CommandIdentifier{
+	global.ReadAttributesID:             global.ReadAttributesResponseID,
+	global.WriteAttributesID:            global.WriteAttributesResponseID,
+	global.WriteAttributesUndividedID:   global.WriteAttributesResponseID,
+	global.ConfigureReportingID:         global.ConfigureReportingResponseID,
+	global.ReadReportingConfigurationID: global.ReadReportingConfigurationResponseID,
+	global.DiscoverAttributesID:         global.DiscoverAttributesResponseID,
+	global.ReadAttributesStructuredID:   global.ReadAttributesResponseID,
+	global.WriteAttributesStructuredID:  global.WriteAttributesStructuredResponseID,
+	global.DiscoverCommandsReceivedID:   global.DiscoverCommandsReceivedResponseID,
+	global.DiscoverCommandsGeneratedID:  global.DiscoverCommandsGeneratedResponseID,
+	global.DiscoverAttributesExtendedID: global.DiscoverAttributesExtendedResponseID,
+}
+
+// expectsResponse returns true if the request has a specific response, which is sent even if the Default Response is
+// disabled. Local commands have one if it was registered with RegisterLocalResponse.
+func (c *communicator) expectsResponse(request zcl.Message, requestIdentifier zcl.CommandIdentifier) bool {
+	if request.FrameType == zcl.FrameGlobal {
+		_, found := globalResponses[requestIdentifier]
+		return found
+	}
+
+	_, found := c.CommandRegistry.LocalCommandResponse(request.ClusterID, request.Manufacturer, request.Direction, requestIdentifier)
+	return found
+}
+
+// isResponse returns true if the message is a response to a request from the node, which reuses the node's transaction
+// sequence. Local commands are responses if they were registered with RegisterLocalResponse.
+func (c *communicator) isResponse(message zcl.Message, identifier zcl.CommandIdentifier) bool {
+	if message.FrameType == zcl.FrameGlobal {
+		if identifier == global.DefaultResponseID {
+			return true
+		}
+
+		for _, responseIdentifier := range globalResponses {
+			if responseIdentifier == identifier {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return c.CommandRegistry.IsLocalCommandResponse(message.ClusterID, message.Manufacturer, message.Direction, identifier)
+}
+
+// ResponseMatch matches the response to a request, it must come from the node, cluster and endpoint the request was
+// sent to, in the opposite direction and with the same transaction sequence. Requests to the broadcast endpoint are
+// answered from any endpoint. Global requests match their specific response, local requests match any local command,
+// and both match a Default Response for the request's command.
+func ResponseMatch(address zigbee.IEEEAddress, request zcl.Message, requestIdentifier zcl.CommandIdentifier) Matcher {
+	var expected Matcher
+
+	if request.FrameType == zcl.FrameGlobal {
+		if responseIdentifier, found := globalResponses[requestIdentifier]; found {
+			expected = CommandMatch(zcl.FrameGlobal, responseIdentifier)
+		} else {
+			expected = And(FrameTypeMatch(zcl.FrameGlobal), Not(CommandMatch(zcl.FrameGlobal, global.ReportAttributesID)))
+		}
+	} else {
+		expected = FrameTypeMatch(zcl.FrameLocal)
+	}
+
+	matchers := []Matcher{
+		AddressAndSequenceMatch(address, request.TransactionSequence),
+		ClusterMatch(request.ClusterID),
+		DirectionMatch(oppositeDirection(request.Direction)),
+		Or(expected, DefaultResponseMatch(requestIdentifier)),
+	}
+
+	if request.DestinationEndpoint != broadcastEndpoint {
+		matchers = append(matchers, SourceEndpointMatch(request.DestinationEndpoint))
+	}
+
+	return And(matchers...)
+}
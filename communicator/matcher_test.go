@@ -0,0 +1,191 @@
+package communicator
+
+import (
+	"context"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestMatchers(t *testing.T) {
+	address := zigbee.IEEEAddress(1)
+
+	message := zcl.Message{
+		FrameType:           zcl.FrameGlobal,
+		Direction:           zcl.ServerToClient,
+		TransactionSequence: 4,
+		ClusterID:           0x0006,
+		SourceEndpoint:      2,
+		CommandIdentifier:   global.DefaultResponseID,
+		Command:             &global.DefaultResponse{CommandIdentifier: uint8(global.ReadAttributesID), Status: zcl.StatusUnsupportedGeneralCommand},
+	}
+
+	match := func(matcher Matcher) bool {
+		return matcher(address, zigbee.ApplicationMessage{}, message)
+	}
+
+	t.Run("predicates match the message", func(t *testing.T) {
+		assert.True(t, match(AddressMatch(1)))
+		assert.False(t, match(AddressMatch(2)))
+		assert.True(t, match(SequenceMatch(4)))
+		assert.False(t, match(SequenceMatch(5)))
+		assert.True(t, match(ClusterMatch(0x0006)))
+		assert.False(t, match(ClusterMatch(0x0008)))
+		assert.True(t, match(SourceEndpointMatch(2)))
+		assert.False(t, match(SourceEndpointMatch(1)))
+		assert.True(t, match(DirectionMatch(zcl.ServerToClient)))
+		assert.False(t, match(DirectionMatch(zcl.ClientToServer)))
+		assert.True(t, match(FrameTypeMatch(zcl.FrameGlobal)))
+		assert.False(t, match(FrameTypeMatch(zcl.FrameLocal)))
+		assert.True(t, match(CommandMatch(zcl.FrameGlobal, global.DefaultResponseID)))
+		assert.False(t, match(CommandMatch(zcl.FrameLocal, global.DefaultResponseID)))
+		assert.True(t, match(CommandTypeMatch(&global.DefaultResponse{})))
+		assert.False(t, match(CommandTypeMatch(global.DefaultResponse{})))
+		assert.True(t, match(DefaultResponseMatch(global.ReadAttributesID)))
+		assert.False(t, match(DefaultResponseMatch(global.WriteAttributesID)))
+	})
+
+	t.Run("matchers can be composed", func(t *testing.T) {
+		assert.True(t, match(And()))
+		assert.True(t, match(And(AddressMatch(1), SequenceMatch(4))))
+		assert.False(t, match(And(AddressMatch(1), SequenceMatch(5))))
+		assert.False(t, match(Or()))
+		assert.True(t, match(Or(AddressMatch(2), SequenceMatch(4))))
+		assert.False(t, match(Or(AddressMatch(2), SequenceMatch(5))))
+		assert.True(t, match(Not(AddressMatch(2))))
+	})
+
+	t.Run("response match requires the expected response or a default response", func(t *testing.T) {
+		request := zcl.Message{
+			FrameType:           zcl.FrameGlobal,
+			Direction:           zcl.ClientToServer,
+			TransactionSequence: 4,
+			ClusterID:           0x0006,
+			DestinationEndpoint: 2,
+		}
+
+		assert.True(t, match(ResponseMatch(address, request, global.ReadAttributesID)))
+		assert.False(t, match(ResponseMatch(address, request, global.ConfigureReportingID)))
+
+		response := message
+		response.CommandIdentifier = global.ReadAttributesResponseID
+		response.Command = &global.ReadAttributesResponse{}
+
+		assert.True(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, response))
+
+		report := message
+		report.CommandIdentifier = global.ReportAttributesID
+		report.Command = &global.ReportAttributes{}
+
+		assert.False(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, report))
+		assert.False(t, ResponseMatch(address, request, 0x40)(address, zigbee.ApplicationMessage{}, report))
+
+		request.DestinationEndpoint = 3
+		assert.False(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, response))
+	})
+
+	t.Run("response match accepts any source endpoint if the request was sent to the broadcast endpoint", func(t *testing.T) {
+		request := zcl.Message{
+			FrameType:           zcl.FrameGlobal,
+			Direction:           zcl.ClientToServer,
+			TransactionSequence: 4,
+			ClusterID:           0x0006,
+			DestinationEndpoint: 0xff,
+		}
+
+		response := message
+		response.CommandIdentifier = global.ReadAttributesResponseID
+		response.Command = &global.ReadAttributesResponse{}
+
+		assert.True(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, response))
+
+		response.SourceEndpoint = 7
+		assert.True(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, response))
+
+		response.ClusterID = 0x0008
+		assert.False(t, ResponseMatch(address, request, global.ReadAttributesID)(address, zigbee.ApplicationMessage{}, response))
+	})
+}
+
+func TestCommunicator_RequestResponse_Matching(t *testing.T) {
+	t.Run("an unsolicited report reusing the sequence does not resolve a read", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr)
+		address := zigbee.IEEEAddress(2)
+
+		deliver := func(command interface{}) {
+			appMessage, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: 9,
+				ClusterID:           0x0006,
+				SourceEndpoint:      2,
+				DestinationEndpoint: 1,
+				Command:             command,
+			})
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: address},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+			})
+		}
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, address, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			deliver(&global.ReportAttributes{Records: []global.ReportAttributesRecord{{Identifier: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeBoolean, Value: true}}}})
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				deliver(&global.ReadAttributesResponse{Records: []global.ReadAttributeResponseRecord{{Identifier: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeBoolean, Value: false}}}})
+			}()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		records, err := c.ReadAttributes(ctx, address, false, 0x0006, zigbee.NoManufacturer, 1, 2, 9, []zcl.AttributeID{0})
+		assert.NoError(t, err)
+		assert.Equal(t, []global.ReadAttributeResponseRecord{{Identifier: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeBoolean, Value: false}}}, records)
+	})
+
+	t.Run("a read sent to the broadcast endpoint is resolved by a response from any endpoint", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr)
+		address := zigbee.IEEEAddress(2)
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, address, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			appMessage, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: 9,
+				ClusterID:           0x0006,
+				SourceEndpoint:      3,
+				DestinationEndpoint: 1,
+				Command:             &global.ReadAttributesResponse{Records: []global.ReadAttributeResponseRecord{{Identifier: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeBoolean, Value: true}}}},
+			})
+
+			go c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: address},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+			})
+		}).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		records, err := c.ReadAttributes(ctx, address, false, 0x0006, zigbee.NoManufacturer, 1, 0xff, 9, []zcl.AttributeID{0})
+		assert.NoError(t, err)
+		assert.Equal(t, []global.ReadAttributeResponseRecord{{Identifier: 0, DataTypeValue: &zcl.AttributeDataTypeValue{DataType: zcl.TypeBoolean, Value: true}}}, records)
+
+		provider.AssertExpectations(t)
+	})
+}
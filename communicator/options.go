@@ -0,0 +1,37 @@
+package communicator
+
+import "github.com/shimmeringbee/zcl"
+
+type Option func(*communicator)
+
+// WithAutomaticDefaultResponse causes the communicator to reply to incoming commands with a Default Response once all
+// matching callbacks have completed, unless a callback has already replied using the commands transaction sequence.
+func WithAutomaticDefaultResponse() Option {
+	return func(c *communicator) {
+		c.automaticDefaultResponse = true
+	}
+}
+
+// WithUnmarshalOptions provides options to the CommandRegistry when unmarshalling incoming messages, such as
+// zcl.PreserveUnknownCommands.
+func WithUnmarshalOptions(options ...zcl.UnmarshalOption) Option {
+	return func(c *communicator) {
+		c.unmarshalOptions = append(c.unmarshalOptions, options...)
+	}
+}
+
+// WithAutomaticTransactionSequence causes RequestResponse, and the helpers which use it, to allocate a transaction
+// sequence for each request rather than using the one provided by the caller.
+func WithAutomaticTransactionSequence() Option {
+	return func(c *communicator) {
+		c.automaticTransactionSequence = true
+	}
+}
+
+// WithRetryPolicy sets the retry policy used by Request and RequestResponse, it can be overridden for a single call
+// with ContextWithRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *communicator) {
+		c.defaultRetryPolicy = policy
+	}
+}
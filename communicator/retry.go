@@ -0,0 +1,128 @@
+package communicator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+var ErrNoResponse = errors.New("ZCL communicator waiting for reply, context expired")
+
+// SendError is returned when the provider fails to send a message to the node.
+type SendError struct {
+	Err error
+}
+
+func (e *SendError) Error() string {
+	return "ZCL communicator failed to send via provider: " + e.Err.Error()
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// RetryPolicy controls how Request and RequestResponse retry, the zero value makes a single attempt which waits until
+// the context expires. Retries of RequestResponse reuse the transaction sequence, so a late reply to an earlier
+// attempt completes the request.
+type RetryPolicy struct {
+	// Attempts is the total number of attempts made, values less than one are treated as one.
+	Attempts int
+	// AttemptTimeout limits how long each attempt waits for a reply, zero waits until the context expires.
+	AttemptTimeout time.Duration
+	// Backoff is the delay before the first retry, it is multiplied by Multiplier for each further retry up to
+	// MaxBackoff, if set. A Multiplier less than one is treated as one.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Multiplier float64
+	// Jitter randomly varies each delay by up to this fraction of it, between 0 and 1.
+	Jitter float64
+	// Retryable decides if an attempt which failed with the error should be retried, DefaultRetryable is used if nil.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryable retries attempts which were not replied to or could not be sent by the provider. Status errors
+// from the node and marshalling failures are not retried.
+func DefaultRetryable(err error) bool {
+	var sendErr *SendError
+	return errors.Is(err, ErrNoResponse) || errors.As(err, &sendErr)
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.Attempts < 1 {
+		return 1
+	}
+
+	return p.Attempts
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return DefaultRetryable(err)
+	}
+
+	return p.Retryable(err)
+}
+
+// backoff returns the delay before the next attempt, after the numbered attempt has failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	delay := float64(p.Backoff) * math.Pow(multiplier, float64(attempt-1))
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+func (p RetryPolicy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.AttemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, p.AttemptTimeout)
+}
+
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy overrides the communicator's retry policy for requests made with the returned context.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+func (c *communicator) retryPolicy(ctx context.Context) RetryPolicy {
+	if policy, found := ctx.Value(retryPolicyKey{}).(RetryPolicy); found {
+		return policy
+	}
+
+	return c.defaultRetryPolicy
+}
+
+// retry calls fn until it succeeds, the policy's attempts are exhausted, the error is not retryable or the context
+// expires. The last error is returned.
+func retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := policy.attemptContext(ctx)
+		err := fn(attemptCtx)
+		cancel()
+
+		if err == nil || attempt >= policy.attempts() || ctx.Err() != nil || !policy.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
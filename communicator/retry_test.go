@@ -0,0 +1,177 @@
+package communicator
+
+import (
+	"context"
+	"errors"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("the zero value makes a single attempt", func(t *testing.T) {
+		assert.Equal(t, 1, RetryPolicy{}.attempts())
+		assert.Equal(t, time.Duration(0), RetryPolicy{}.backoff(1))
+	})
+
+	t.Run("backoff is multiplied for each attempt up to the maximum", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: 100 * time.Millisecond, Multiplier: 2, MaxBackoff: 300 * time.Millisecond}
+
+		assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+		assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+		assert.Equal(t, 300*time.Millisecond, policy.backoff(3))
+	})
+
+	t.Run("jitter varies the backoff within the fraction", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: 100 * time.Millisecond, Jitter: 0.5}
+
+		for i := 0; i < 100; i++ {
+			delay := policy.backoff(1)
+			assert.GreaterOrEqual(t, int64(delay), int64(50*time.Millisecond))
+			assert.LessOrEqual(t, int64(delay), int64(150*time.Millisecond))
+		}
+	})
+
+	t.Run("only missing replies and send failures are retried by default", func(t *testing.T) {
+		assert.True(t, DefaultRetryable(ErrNoResponse))
+		assert.True(t, DefaultRetryable(&SendError{Err: errors.New("fail")}))
+		assert.False(t, DefaultRetryable(zcl.NewStatusError(zcl.StatusFailure, 0x0006, 0x00)))
+		assert.False(t, DefaultRetryable(errors.New("marshal")))
+	})
+}
+
+func TestCommunicator_Retry(t *testing.T) {
+	ieee := zigbee.IEEEAddress(2)
+
+	request := zcl.Message{
+		FrameType:           zcl.FrameGlobal,
+		Direction:           zcl.ClientToServer,
+		TransactionSequence: 0x20,
+		ClusterID:           0x0006,
+		SourceEndpoint:      1,
+		DestinationEndpoint: 2,
+		Command:             &global.ReadAttributes{Identifier: []zcl.AttributeID{0}},
+	}
+
+	setup := func(options ...Option) (*zigbee.MockProvider, *zcl.CommandRegistry, Communicator) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		return provider, cr, NewCommunicator(provider, cr, options...)
+	}
+
+	reply := func(c Communicator, cr *zcl.CommandRegistry, command interface{}) {
+		appMessage, _ := cr.Marshal(zcl.Message{
+			FrameType:           zcl.FrameGlobal,
+			Direction:           zcl.ServerToClient,
+			TransactionSequence: 0x20,
+			ClusterID:           0x0006,
+			SourceEndpoint:      2,
+			DestinationEndpoint: 1,
+			Command:             command,
+		})
+
+		c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+			Node:            zigbee.Node{IEEEAddress: ieee},
+			IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: appMessage},
+		})
+	}
+
+	t.Run("an attempt without a reply is retried with the same transaction sequence", func(t *testing.T) {
+		provider, cr, c := setup(WithRetryPolicy(RetryPolicy{Attempts: 3, AttemptTimeout: 20 * time.Millisecond}))
+
+		mutex := &sync.Mutex{}
+		var sequences []uint8
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			message, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+
+			mutex.Lock()
+			sequences = append(sequences, message.TransactionSequence)
+			attempt := len(sequences)
+			mutex.Unlock()
+
+			if attempt == 2 {
+				reply(c, cr, &global.ReadAttributesResponse{Records: []global.ReadAttributeResponseRecord{}})
+			}
+		})
+
+		response, err := c.RequestResponse(context.Background(), ieee, false, request)
+		assert.NoError(t, err)
+		assert.IsType(t, &global.ReadAttributesResponse{}, response.Command)
+		assert.Equal(t, []uint8{0x20, 0x20}, sequences)
+	})
+
+	t.Run("no response is returned once attempts are exhausted", func(t *testing.T) {
+		provider, _, c := setup(WithRetryPolicy(RetryPolicy{Attempts: 2, AttemptTimeout: 10 * time.Millisecond}))
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Times(2)
+
+		_, err := c.RequestResponse(context.Background(), ieee, false, request)
+		assert.ErrorIs(t, err, ErrNoResponse)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("send failures are retried by Request", func(t *testing.T) {
+		provider, _, c := setup(WithRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+
+		sendErr := errors.New("no route")
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, true).Return(sendErr).Once()
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, true).Return(nil).Once()
+
+		err := c.Request(context.Background(), ieee, true, request)
+		assert.NoError(t, err)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("status errors are not retried", func(t *testing.T) {
+		provider, cr, c := setup(WithRetryPolicy(RetryPolicy{Attempts: 3}))
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(nil).Once().Run(func(args mock.Arguments) {
+			reply(c, cr, &global.DefaultResponse{CommandIdentifier: uint8(global.ReadAttributesID), Status: zcl.StatusUnsupportedGeneralCommand})
+		})
+
+		_, err := c.RequestResponse(context.Background(), ieee, false, request)
+
+		var statusErr *zcl.StatusError
+		assert.True(t, errors.As(err, &statusErr))
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("the policy can be overridden per call with the context", func(t *testing.T) {
+		provider, _, c := setup()
+
+		sendErr := errors.New("no route")
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(sendErr).Times(2)
+
+		ctx := ContextWithRetryPolicy(context.Background(), RetryPolicy{Attempts: 2})
+
+		err := c.Request(ctx, ieee, false, request)
+
+		var errSend *SendError
+		assert.True(t, errors.As(err, &errSend))
+		assert.ErrorIs(t, err, sendErr)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("retries stop when the context expires", func(t *testing.T) {
+		provider, _, c := setup(WithRetryPolicy(RetryPolicy{Attempts: 100, Backoff: time.Hour}))
+		provider.On("SendApplicationMessageToNode", mock.Anything, ieee, mock.Anything, false).Return(errors.New("no route")).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := c.Request(ctx, ieee, false, request)
+		assert.Error(t, err)
+
+		provider.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,190 @@
+package communicator
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zigbee"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNoTransactionSequenceAvailable = errors.New("no transaction sequence available, all are in flight")
+	ErrTransactionSequenceInUse       = errors.New("transaction sequence is already in use by an in flight request")
+)
+
+/*
+ * Transaction sequences are allocated per node, each node starts from a random sequence and increments, wrapping at
+ * 255 and skipping any sequence which is in use. A sequence is in use while a request using it is in flight, or while
+ * it is held by a caller of AllocateTransactionSequence, so a late reply can not be confused with a new request. Nodes
+ * with no sequence in use are forgotten once they have been idle for sequenceIdleTimeout.
+ */
+const sequenceIdleTimeout = 5 * time.Minute
+
+// allocatorCount distinguishes the seeds of allocators created within the resolution of the clock.
+var allocatorCount int64
+
+type sequenceAllocator struct {
+	mutex     *sync.Mutex
+	nodes     map[zigbee.IEEEAddress]*nodeSequences
+	random    *rand.Rand
+	now       func() time.Time
+	lastEvict time.Time
+}
+
+type nodeSequences struct {
+	next       uint8
+	inFlight   [256]bool
+	allocated  [256]bool
+	inUseCount int
+	lastUsed   time.Time
+}
+
+func (n *nodeSequences) inUse(sequence uint8) bool {
+	return n.inFlight[sequence] || n.allocated[sequence]
+}
+
+// set marks or clears a sequence as in flight or held, keeping count of the sequences in use.
+func (n *nodeSequences) set(flags *[256]bool, sequence uint8, value bool) {
+	wasInUse := n.inUse(sequence)
+	flags[sequence] = value
+
+	if isInUse := n.inUse(sequence); isInUse && !wasInUse {
+		n.inUseCount++
+	} else if !isInUse && wasInUse {
+		n.inUseCount--
+	}
+}
+
+func newSequenceAllocator() *sequenceAllocator {
+	return &sequenceAllocator{
+		mutex:  &sync.Mutex{},
+		nodes:  map[zigbee.IEEEAddress]*nodeSequences{},
+		random: rand.New(rand.NewSource(time.Now().UnixNano() + atomic.AddInt64(&allocatorCount, 1))),
+		now:    time.Now,
+	}
+}
+
+func (s *sequenceAllocator) node(address zigbee.IEEEAddress) *nodeSequences {
+	now := s.now()
+	s.evictIdle(now)
+
+	node, found := s.nodes[address]
+
+	if !found {
+		node = &nodeSequences{next: uint8(s.random.Intn(256))}
+		s.nodes[address] = node
+	}
+
+	node.lastUsed = now
+	return node
+}
+
+// evictIdle forgets nodes which have no sequence in use and have been idle for sequenceIdleTimeout, checking at most
+// once per timeout.
+func (s *sequenceAllocator) evictIdle(now time.Time) {
+	if now.Sub(s.lastEvict) < sequenceIdleTimeout {
+		return
+	}
+
+	s.lastEvict = now
+
+	for address, node := range s.nodes {
+		if node.inUseCount == 0 && now.Sub(node.lastUsed) >= sequenceIdleTimeout {
+			delete(s.nodes, address)
+		}
+	}
+}
+
+// next returns the next sequence for the node which is not in use.
+func (s *sequenceAllocator) next(node *nodeSequences) (uint8, error) {
+	for i := 0; i < len(node.inFlight); i++ {
+		sequence := node.next
+		node.next++
+
+		if !node.inUse(sequence) {
+			return sequence, nil
+		}
+	}
+
+	return 0, ErrNoTransactionSequenceAvailable
+}
+
+// allocate returns the next sequence for the node which is not in use, and reserves it as in flight.
+func (s *sequenceAllocator) allocate(address zigbee.IEEEAddress) (uint8, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	node := s.node(address)
+	sequence, err := s.next(node)
+
+	if err != nil {
+		return 0, err
+	}
+
+	node.set(&node.inFlight, sequence, true)
+	return sequence, nil
+}
+
+// reserve marks a caller provided sequence as in flight, returning false if a request using it is already in flight.
+// Sequences held by callers of AllocateTransactionSequence may be reserved.
+func (s *sequenceAllocator) reserve(address zigbee.IEEEAddress, sequence uint8) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	node := s.node(address)
+
+	if node.inFlight[sequence] {
+		return false
+	}
+
+	node.set(&node.inFlight, sequence, true)
+	return true
+}
+
+func (s *sequenceAllocator) release(address zigbee.IEEEAddress, sequence uint8) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if node, found := s.nodes[address]; found {
+		node.set(&node.inFlight, sequence, false)
+		node.lastUsed = s.now()
+	}
+}
+
+func (s *sequenceAllocator) hold(address zigbee.IEEEAddress) (uint8, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	node := s.node(address)
+	sequence, err := s.next(node)
+
+	if err != nil {
+		return 0, err
+	}
+
+	node.set(&node.allocated, sequence, true)
+	return sequence, nil
+}
+
+func (s *sequenceAllocator) releaseHold(address zigbee.IEEEAddress, sequence uint8) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if node, found := s.nodes[address]; found {
+		node.set(&node.allocated, sequence, false)
+		node.lastUsed = s.now()
+	}
+}
+
+// AllocateTransactionSequence returns a transaction sequence for the node which is not in use by any other transaction
+// made through the communicator. The sequence is held until ReleaseTransactionSequence is called, and may be used for
+// any number of requests in that time, though only one may be in flight at once.
+func (c *communicator) AllocateTransactionSequence(address zigbee.IEEEAddress) (uint8, error) {
+	return c.sequences.hold(address)
+}
+
+func (c *communicator) ReleaseTransactionSequence(address zigbee.IEEEAddress, sequence uint8) {
+	c.sequences.releaseHold(address, sequence)
+}
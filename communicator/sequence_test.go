@@ -0,0 +1,335 @@
+package communicator
+
+import (
+	"context"
+	"github.com/shimmeringbee/zcl"
+	"github.com/shimmeringbee/zcl/commands/global"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func Test_sequenceAllocator(t *testing.T) {
+	address := zigbee.IEEEAddress(1)
+
+	t.Run("allocates sequences in order and wraps at 255", func(t *testing.T) {
+		s := newSequenceAllocator()
+		s.node(address).next = 254
+
+		for _, expected := range []uint8{254, 255, 0, 1} {
+			sequence, err := s.allocate(address)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, sequence)
+		}
+	})
+
+	t.Run("skips sequences which are in flight", func(t *testing.T) {
+		s := newSequenceAllocator()
+		s.node(address).next = 10
+
+		assert.True(t, s.reserve(address, 11))
+		assert.False(t, s.reserve(address, 11))
+
+		first, _ := s.allocate(address)
+		second, _ := s.allocate(address)
+
+		assert.Equal(t, uint8(10), first)
+		assert.Equal(t, uint8(12), second)
+	})
+
+	t.Run("skips sequences which are held by a caller", func(t *testing.T) {
+		s := newSequenceAllocator()
+		s.node(address).next = 10
+
+		held, _ := s.hold(address)
+		allocated, _ := s.allocate(address)
+
+		assert.Equal(t, uint8(10), held)
+		assert.Equal(t, uint8(11), allocated)
+		assert.True(t, s.reserve(address, held))
+	})
+
+	t.Run("allocations are independent per node", func(t *testing.T) {
+		s := newSequenceAllocator()
+		s.node(address).next = 10
+		s.node(address + 1).next = 10
+
+		first, _ := s.allocate(address)
+		second, _ := s.allocate(address + 1)
+
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("idle nodes are forgotten, but not while a sequence is in use", func(t *testing.T) {
+		s := newSequenceAllocator()
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		_, _ = s.allocate(address)
+		held, _ := s.hold(address + 1)
+
+		now = now.Add(sequenceIdleTimeout)
+		_, _ = s.allocate(address + 2)
+
+		assert.Len(t, s.nodes, 3)
+
+		s.release(address, s.nodes[address].next-1)
+		s.releaseHold(address+1, held)
+
+		now = now.Add(sequenceIdleTimeout / 2)
+		_, _ = s.allocate(address + 2)
+
+		assert.Len(t, s.nodes, 3)
+
+		now = now.Add(sequenceIdleTimeout / 2)
+		_, _ = s.allocate(address + 2)
+
+		assert.Len(t, s.nodes, 1)
+		assert.Contains(t, s.nodes, address+2)
+	})
+
+	t.Run("nodes start from a random sequence", func(t *testing.T) {
+		starts := map[uint8]bool{}
+
+		for i := 0; i < 16; i++ {
+			starts[newSequenceAllocator().node(address).next] = true
+		}
+
+		assert.Greater(t, len(starts), 1)
+	})
+
+	t.Run("returns an error when all sequences are in flight, until one is released", func(t *testing.T) {
+		s := newSequenceAllocator()
+
+		for i := 0; i < 256; i++ {
+			_, err := s.allocate(address)
+			assert.NoError(t, err)
+		}
+
+		_, err := s.allocate(address)
+		assert.ErrorIs(t, err, ErrNoTransactionSequenceAvailable)
+
+		s.release(address, 42)
+		s.release(address, 42)
+
+		sequence, err := s.allocate(address)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(42), sequence)
+	})
+}
+
+func TestCommunicator_TransactionSequence(t *testing.T) {
+	expectedIEEE := zigbee.IEEEAddress(2)
+
+	replyTo := func(c Communicator, cr *zcl.CommandRegistry) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			request, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+
+			reply, _ := cr.Marshal(zcl.Message{
+				FrameType:           zcl.FrameGlobal,
+				Direction:           zcl.ServerToClient,
+				TransactionSequence: request.TransactionSequence,
+				ClusterID:           request.ClusterID,
+				SourceEndpoint:      request.DestinationEndpoint,
+				DestinationEndpoint: request.SourceEndpoint,
+				Command:             &global.ReadAttributesResponse{Records: []global.ReadAttributeResponseRecord{}},
+			})
+
+			c.ProcessIncomingMessage(zigbee.NodeIncomingMessageEvent{
+				Node:            zigbee.Node{IEEEAddress: expectedIEEE},
+				IncomingMessage: zigbee.IncomingMessage{ApplicationMessage: reply},
+			})
+		}
+	}
+
+	request := zcl.Message{
+		FrameType:           zcl.FrameGlobal,
+		Direction:           zcl.ClientToServer,
+		TransactionSequence: 7,
+		ClusterID:           0x0001,
+		SourceEndpoint:      1,
+		DestinationEndpoint: 2,
+		Command:             &global.ReadAttributes{Identifier: []zcl.AttributeID{1}},
+	}
+
+	t.Run("an explicit sequence is reserved while the request is in flight", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr).(*communicator)
+		c.sequences.node(expectedIEEE).next = 7
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			sequence, err := c.AllocateTransactionSequence(expectedIEEE)
+			assert.NoError(t, err)
+			assert.Equal(t, uint8(8), sequence)
+			c.ReleaseTransactionSequence(expectedIEEE, sequence)
+
+			replyTo(c, cr)(args)
+		})
+
+		_, err := c.RequestResponse(context.Background(), expectedIEEE, false, request)
+		assert.NoError(t, err)
+
+		sequence, err := c.AllocateTransactionSequence(expectedIEEE)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(9), sequence)
+		assert.True(t, c.sequences.reserve(expectedIEEE, 7))
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("an allocated sequence provided by the caller is not released by the request", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr)
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Run(replyTo(c, cr))
+
+		sequence, err := c.(TransactionSequencer).AllocateTransactionSequence(expectedIEEE)
+		assert.NoError(t, err)
+
+		_, err = c.ReadAttributes(context.Background(), expectedIEEE, false, 0x0001, zigbee.NoManufacturer, 1, 2, sequence, []zcl.AttributeID{1})
+		assert.NoError(t, err)
+
+		node := c.(*communicator).sequences.node(expectedIEEE)
+		assert.True(t, node.allocated[sequence])
+		assert.False(t, node.inFlight[sequence])
+
+		c.(TransactionSequencer).ReleaseTransactionSequence(expectedIEEE, sequence)
+		assert.False(t, node.allocated[sequence])
+	})
+
+	t.Run("a request and response using a sequence which is already in flight is rejected without sending", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr).(*communicator)
+		assert.True(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+
+		_, err := c.RequestResponse(context.Background(), expectedIEEE, false, request)
+		assert.ErrorIs(t, err, ErrTransactionSequenceInUse)
+
+		provider.AssertNotCalled(t, "SendApplicationMessageToNode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a request which is not waited for is sent with its sequence even if it is in flight", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr).(*communicator)
+		assert.True(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Once()
+
+		err := c.Request(context.Background(), expectedIEEE, false, request)
+		assert.NoError(t, err)
+		assert.False(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("sequences held by a caller are not allocated automatically", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr, WithAutomaticTransactionSequence()).(*communicator)
+		c.sequences.node(expectedIEEE).next = 50
+
+		held, err := c.AllocateTransactionSequence(expectedIEEE)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(50), held)
+
+		var sent []uint8
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			message, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+			sent = append(sent, message.TransactionSequence)
+
+			replyTo(c, cr)(args)
+		})
+
+		c.sequences.node(expectedIEEE).next = 50
+
+		_, err = c.RequestResponse(context.Background(), expectedIEEE, false, request)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []uint8{51}, sent)
+	})
+
+	t.Run("a request which is not waited for reserves its sequence while it is sent", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr).(*communicator)
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			assert.False(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+		})
+
+		err := c.Request(context.Background(), expectedIEEE, false, request)
+		assert.NoError(t, err)
+		assert.True(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("a response to the node may use a sequence which is in flight", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr).(*communicator)
+		assert.True(t, c.sequences.reserve(expectedIEEE, request.TransactionSequence))
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil)
+
+		err := c.Request(context.Background(), expectedIEEE, false, zcl.Message{
+			FrameType:           zcl.FrameGlobal,
+			Direction:           zcl.ClientToServer,
+			TransactionSequence: request.TransactionSequence,
+			ClusterID:           0x0001,
+			SourceEndpoint:      1,
+			DestinationEndpoint: 2,
+			Command:             &global.DefaultResponse{CommandIdentifier: uint8(global.ReportAttributesID)},
+		})
+		assert.NoError(t, err)
+
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("sequences are allocated automatically when requested", func(t *testing.T) {
+		provider := &zigbee.MockProvider{}
+		cr := zcl.NewCommandRegistry()
+		global.Register(cr)
+
+		c := NewCommunicator(provider, cr, WithAutomaticTransactionSequence()).(*communicator)
+		c.sequences.node(expectedIEEE).next = 100
+
+		var sent []uint8
+
+		provider.On("SendApplicationMessageToNode", mock.Anything, expectedIEEE, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+			message, _ := cr.Unmarshal(args.Get(2).(zigbee.ApplicationMessage))
+			sent = append(sent, message.TransactionSequence)
+
+			replyTo(c, cr)(args)
+		})
+
+		for i := 0; i < 2; i++ {
+			response, err := c.RequestResponse(context.Background(), expectedIEEE, false, request)
+			assert.NoError(t, err)
+			assert.Equal(t, uint8(100+i), response.TransactionSequence)
+		}
+
+		assert.Equal(t, []uint8{100, 101}, sent)
+		assert.True(t, c.sequences.reserve(expectedIEEE, 100))
+	})
+}
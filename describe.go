@@ -0,0 +1,401 @@
+package zcl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// MessageDescription is a human readable rendering of a Message, using the names of clusters, commands and attributes
+// held in a CommandRegistry. It is produced by CommandRegistry.Describe.
+type MessageDescription struct {
+	FrameType           FrameType
+	Direction           Direction
+	TransactionSequence uint8
+	Manufacturer        zigbee.ManufacturerCode
+	Cluster             string
+	SourceEndpoint      zigbee.Endpoint
+	DestinationEndpoint zigbee.Endpoint
+	Command             string
+	Payload             DescribedValue
+	TrailingData        []byte
+}
+
+// DescribedValue is a node in the description of a command, either a Value or a list of Fields.
+type DescribedValue struct {
+	Name   string
+	Value  string
+	Fields []DescribedValue
+	List   bool
+}
+
+// Describe renders a Message for logging and debugging, it never fails, values which can not be named are rendered
+// numerically.
+func (cr *CommandRegistry) Describe(message Message) MessageDescription {
+	d := describer{cr: cr, clusterID: message.ClusterID, manufacturer: message.Manufacturer}
+
+	return MessageDescription{
+		FrameType:           message.FrameType,
+		Direction:           message.Direction,
+		TransactionSequence: message.TransactionSequence,
+		Manufacturer:        message.Manufacturer,
+		Cluster:             describeCluster(message.ClusterID),
+		SourceEndpoint:      message.SourceEndpoint,
+		DestinationEndpoint: message.DestinationEndpoint,
+		Command:             fmt.Sprintf("%s(0x%02x)", cr.describeCommandName(message), uint8(message.CommandIdentifier)),
+		Payload:             d.describe("", reflect.ValueOf(message.Command)),
+		TrailingData:        message.TrailingData,
+	}
+}
+
+func (cr *CommandRegistry) describeCommandName(message Message) string {
+	var name string
+	var found bool
+
+	if !isUnknownCommand(message.Command) {
+		if message.FrameType == FrameGlobal {
+			name, found = cr.GlobalCommandName(message.CommandIdentifier)
+		} else {
+			name, found = cr.LocalCommandName(message.ClusterID, message.Manufacturer, message.Direction, message.CommandIdentifier)
+
+			if !found && message.Manufacturer != zigbee.NoManufacturer {
+				name, found = cr.LocalCommandName(message.ClusterID, zigbee.NoManufacturer, message.Direction, message.CommandIdentifier)
+			}
+		}
+	}
+
+	if !found {
+		return "Unknown"
+	}
+
+	return name
+}
+
+func describeCluster(clusterID zigbee.ClusterID) string {
+	if _, found := ClusterList[clusterID]; !found {
+		return fmt.Sprintf("0x%04x", uint16(clusterID))
+	}
+
+	return fmt.Sprintf("%s(0x%04x)", ClusterShortName(clusterID), uint16(clusterID))
+}
+
+func (f FrameType) String() string {
+	if name, found := frameTypeNames[f]; found {
+		return name
+	}
+
+	return fmt.Sprintf("frame(%d)", uint8(f))
+}
+
+func (d Direction) String() string {
+	if name, found := directionNames[d]; found {
+		return name
+	}
+
+	return fmt.Sprintf("direction(%d)", uint8(d))
+}
+
+func (m MessageDescription) header() string {
+	header := fmt.Sprintf("%s %s %s cluster=%s tsn=%d endpoints=%d->%d", m.FrameType, m.Command, m.Direction, m.Cluster, m.TransactionSequence, m.SourceEndpoint, m.DestinationEndpoint)
+
+	if m.Manufacturer != zigbee.NoManufacturer {
+		header += fmt.Sprintf(" manufacturer=0x%04x", uint16(m.Manufacturer))
+	}
+
+	return header
+}
+
+// String renders the description on a single line.
+func (m MessageDescription) String() string {
+	sb := &strings.Builder{}
+	sb.WriteString(m.header())
+	sb.WriteString(" ")
+	m.Payload.writeInline(sb)
+
+	if len(m.TrailingData) > 0 {
+		fmt.Fprintf(sb, " trailing=%s", hex.EncodeToString(m.TrailingData))
+	}
+
+	return sb.String()
+}
+
+// Multiline renders the description with each field of the command on its own indented line.
+func (m MessageDescription) Multiline() string {
+	sb := &strings.Builder{}
+	sb.WriteString(m.header())
+
+	for _, field := range m.Payload.Fields {
+		field.writeIndented(sb, 1)
+	}
+
+	if m.Payload.Fields == nil && m.Payload.Value != "" {
+		fmt.Fprintf(sb, "\n  %s", m.Payload.Value)
+	}
+
+	if len(m.TrailingData) > 0 {
+		fmt.Fprintf(sb, "\n  trailing: %s", hex.EncodeToString(m.TrailingData))
+	}
+
+	return sb.String()
+}
+
+func (v DescribedValue) writeInline(sb *strings.Builder) {
+	if v.Fields == nil {
+		sb.WriteString(v.Value)
+		return
+	}
+
+	open, close := "{", "}"
+	if v.List {
+		open, close = "[", "]"
+	}
+
+	sb.WriteString(open)
+
+	for i, field := range v.Fields {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+
+		if !v.List {
+			sb.WriteString(field.Name)
+			sb.WriteString("=")
+		}
+
+		field.writeInline(sb)
+	}
+
+	sb.WriteString(close)
+}
+
+func (v DescribedValue) writeIndented(sb *strings.Builder, depth int) {
+	fmt.Fprintf(sb, "\n%s%s:", strings.Repeat("  ", depth), v.Name)
+
+	if v.Fields == nil {
+		sb.WriteString(" ")
+		sb.WriteString(v.Value)
+		return
+	}
+
+	if len(v.Fields) == 0 {
+		sb.WriteString(" []")
+		return
+	}
+
+	for _, field := range v.Fields {
+		field.writeIndented(sb, depth+1)
+	}
+}
+
+type describer struct {
+	cr           *CommandRegistry
+	clusterID    zigbee.ClusterID
+	manufacturer zigbee.ManufacturerCode
+}
+
+var (
+	attributeIDType = reflect.TypeOf(AttributeID(0))
+	clusterIDType   = reflect.TypeOf(zigbee.ClusterID(0))
+	statusType      = reflect.TypeOf(Status(0))
+	dataTypeType    = reflect.TypeOf(AttributeDataType(0))
+	stringerType    = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+func (d describer) describe(name string, v reflect.Value) DescribedValue {
+	if !v.IsValid() {
+		return DescribedValue{Name: name, Value: "nil"}
+	}
+
+	switch value := v.Interface().(type) {
+	case *AttributeDataTypeValue:
+		if value == nil {
+			return DescribedValue{Name: name, Value: "nil"}
+		}
+
+		return DescribedValue{Name: name, Value: describeAttributeValue(*value)}
+	case AttributeDataTypeValue:
+		return DescribedValue{Name: name, Value: describeAttributeValue(value)}
+	case *UnknownCommand:
+		return DescribedValue{Name: name, Value: hex.EncodeToString(value.Payload)}
+	case UnknownCommand:
+		return DescribedValue{Name: name, Value: hex.EncodeToString(value.Payload)}
+	case []byte:
+		return DescribedValue{Name: name, Value: hex.EncodeToString(value)}
+	}
+
+	switch v.Type() {
+	case attributeIDType:
+		id := AttributeID(v.Uint())
+
+		if attributeName, found := d.cr.AttributeName(d.clusterID, d.manufacturer, id); found {
+			return DescribedValue{Name: name, Value: fmt.Sprintf("%s(0x%04x)", attributeName, uint16(id))}
+		}
+
+		return DescribedValue{Name: name, Value: fmt.Sprintf("0x%04x", uint16(id))}
+	case clusterIDType:
+		return DescribedValue{Name: name, Value: describeCluster(zigbee.ClusterID(v.Uint()))}
+	}
+
+	if v.Type().Implements(stringerType) && v.Kind() != reflect.Ptr {
+		return DescribedValue{Name: name, Value: v.Interface().(fmt.Stringer).String()}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return DescribedValue{Name: name, Value: "nil"}
+		}
+
+		return d.describe(name, v.Elem())
+	case reflect.Struct:
+		return d.describeStruct(name, v)
+	case reflect.Slice, reflect.Array:
+		described := DescribedValue{Name: name, Fields: []DescribedValue{}, List: true}
+
+		for i := 0; i < v.Len(); i++ {
+			described.Fields = append(described.Fields, d.describe(fmt.Sprintf("[%d]", i), v.Index(i)))
+		}
+
+		return described
+	case reflect.String:
+		return DescribedValue{Name: name, Value: fmt.Sprintf("%q", v.String())}
+	}
+
+	return DescribedValue{Name: name, Value: fmt.Sprintf("%v", v.Interface())}
+}
+
+// describeStruct renders the fields of a struct, single bit boolean fields are collected into a Flags field listing
+// those which are set, and reserved fields are omitted if zero.
+func (d describer) describeStruct(name string, v reflect.Value) DescribedValue {
+	described := DescribedValue{Name: name, Fields: []DescribedValue{}}
+
+	var flags []string
+	flagsIndex := -1
+	previousDataType, foundDataType := TypeUnknown, false
+
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := v.Type().Field(i)
+		field := v.Field(i)
+
+		if !field.CanInterface() {
+			continue
+		}
+
+		if fieldType.Type.Kind() == reflect.Bool && fieldType.Tag.Get("bcfieldwidth") == "1" {
+			if flagsIndex < 0 {
+				flagsIndex = len(described.Fields)
+				described.Fields = append(described.Fields, DescribedValue{Name: "Flags"})
+			}
+
+			if field.Bool() {
+				flags = append(flags, fieldType.Name)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(fieldType.Name, "Reserved") && field.IsZero() {
+			continue
+		}
+
+		switch {
+		case fieldType.Type == dataTypeType:
+			previousDataType, foundDataType = AttributeDataType(field.Uint()), true
+		case fieldType.Name == "Status" && fieldType.Type.Kind() == reflect.Uint8 && fieldType.Type != statusType:
+			described.Fields = append(described.Fields, DescribedValue{Name: fieldType.Name, Value: Status(field.Uint()).String()})
+			continue
+		}
+
+		if value, ok := field.Interface().(*AttributeDataValue); ok && foundDataType {
+			if value == nil {
+				continue
+			}
+
+			described.Fields = append(described.Fields, DescribedValue{Name: fieldType.Name, Value: describeValue(previousDataType, value.Value)})
+			continue
+		}
+
+		described.Fields = append(described.Fields, d.describe(fieldType.Name, field))
+	}
+
+	if flagsIndex >= 0 {
+		if len(flags) == 0 {
+			described.Fields[flagsIndex].Value = "none"
+		} else {
+			described.Fields[flagsIndex].Value = strings.Join(flags, "|")
+		}
+
+		if len(described.Fields) == 1 {
+			return DescribedValue{Name: name, Value: described.Fields[0].Value}
+		}
+	}
+
+	return described
+}
+
+// describeTypedValue renders a ZCL value with its type, such as "uint8(42)".
+func describeTypedValue(dt AttributeDataType, v interface{}) string {
+	return fmt.Sprintf("%s(%s)", dt, describeValue(dt, v))
+}
+
+func describeAttributeValue(a AttributeDataTypeValue) string {
+	if a.Invalid {
+		return describeTypedValue(a.DataType, NonValue{})
+	}
+
+	return describeTypedValue(a.DataType, a.Value)
+}
+
+func describeValue(dt AttributeDataType, v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "nil"
+	case NonValue:
+		return "non-value"
+	case []byte:
+		return hex.EncodeToString(value)
+	case string:
+		if isOctetType(dt) {
+			return hex.EncodeToString([]byte(value))
+		}
+
+		return fmt.Sprintf("%q", value)
+	case float32:
+		return fmt.Sprintf("%g", value)
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return fmt.Sprintf("%v", value)
+		}
+
+		return fmt.Sprintf("%g", value)
+	case zigbee.ClusterID:
+		return describeCluster(value)
+	case zigbee.NetworkKey:
+		return hex.EncodeToString(value[:])
+	case TimeOfDay:
+		return fmt.Sprintf("%02d:%02d:%02d.%02d", value.Hours, value.Minutes, value.Seconds, value.Hundredths)
+	case Date:
+		return fmt.Sprintf("%04d-%02d-%02d", 1900+int(value.Year), value.Month, value.DayOfMonth)
+	case []AttributeDataTypeValue:
+		items := make([]string, len(value))
+
+		for i, item := range value {
+			items[i] = describeAttributeValue(item)
+		}
+
+		return "{" + strings.Join(items, " ") + "}"
+	case AttributeSlice:
+		items := make([]string, len(value.Values))
+
+		for i, item := range value.Values {
+			items[i] = describeValue(value.DataType, item)
+		}
+
+		return fmt.Sprintf("%s[%s]", value.DataType, strings.Join(items, " "))
+	}
+
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,39 @@
+//go:build go1.21
+// +build go1.21
+
+package zcl
+
+import (
+	"encoding/hex"
+	"github.com/shimmeringbee/zigbee"
+	"log/slog"
+	"strings"
+)
+
+// LogValue allows a MessageDescription to be logged as a group with log/slog, the payload is rendered on a single line.
+func (m MessageDescription) LogValue() slog.Value {
+	payload := &strings.Builder{}
+	m.Payload.writeInline(payload)
+
+	attrs := []slog.Attr{
+		slog.String("frame", m.FrameType.String()),
+		slog.String("direction", m.Direction.String()),
+		slog.String("cluster", m.Cluster),
+		slog.String("command", m.Command),
+		slog.Int("tsn", int(m.TransactionSequence)),
+		slog.Int("source_endpoint", int(m.SourceEndpoint)),
+		slog.Int("destination_endpoint", int(m.DestinationEndpoint)),
+	}
+
+	if m.Manufacturer != zigbee.NoManufacturer {
+		attrs = append(attrs, slog.Int("manufacturer", int(m.Manufacturer)))
+	}
+
+	attrs = append(attrs, slog.String("payload", payload.String()))
+
+	if len(m.TrailingData) > 0 {
+		attrs = append(attrs, slog.String("trailing", hex.EncodeToString(m.TrailingData)))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,37 @@
+//go:build go1.21
+// +build go1.21
+
+package zcl
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func Test_MessageDescription_LogValue(t *testing.T) {
+	t.Run("logs the description as a group", func(t *testing.T) {
+		cr := NewCommandRegistry()
+
+		message := Message{
+			FrameType:         FrameLocal,
+			ClusterID:         0x0006,
+			CommandIdentifier: 0x05,
+			Command:           &UnknownCommand{Payload: []byte{0xaa}},
+		}
+
+		buffer := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		}}))
+
+		logger.Info("received", "message", cr.Describe(message))
+
+		assert.Equal(t, "level=INFO msg=received message.frame=local message.direction=client_to_server message.cluster=OnOff(0x0006) message.command=Unknown(0x05) message.tsn=0 message.source_endpoint=0 message.destination_endpoint=0 message.payload=aa\n", buffer.String())
+	})
+}
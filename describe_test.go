@@ -0,0 +1,120 @@
+package zcl
+
+import (
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Describe(t *testing.T) {
+	type Record struct {
+		Identifier    AttributeID
+		Status        uint8
+		DataTypeValue *AttributeDataTypeValue
+	}
+
+	type Response struct {
+		Records []Record
+	}
+
+	type Notification struct {
+		Reserved uint8 `bcfieldwidth:"6"`
+		Tamper   bool  `bcfieldwidth:"1"`
+		Alarm1   bool  `bcfieldwidth:"1"`
+		ZoneID   uint8
+	}
+
+	type Reporting struct {
+		DataType AttributeDataType
+		Change   *AttributeDataValue
+	}
+
+	clusterID := zigbee.ClusterID(0x0006)
+
+	cr := NewCommandRegistry()
+	cr.RegisterGlobal(0x01, &Response{})
+	cr.RegisterGlobal(0x02, &Reporting{})
+	cr.RegisterLocal(clusterID, zigbee.NoManufacturer, ServerToClient, 0x00, &Notification{})
+	cr.RegisterAttributes(clusterID, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff", DataType: TypeBoolean, Access: AccessReadReport})
+
+	response := Message{
+		FrameType:           FrameGlobal,
+		Direction:           ServerToClient,
+		TransactionSequence: 0x10,
+		ClusterID:           clusterID,
+		SourceEndpoint:      1,
+		DestinationEndpoint: 2,
+		CommandIdentifier:   0x01,
+		Command: &Response{
+			Records: []Record{
+				{Identifier: 0x0000, DataTypeValue: &AttributeDataTypeValue{DataType: TypeBoolean, Value: true}},
+				{Identifier: 0x0001, Status: uint8(StatusUnsupportedAttribute)},
+			},
+		},
+	}
+
+	t.Run("renders a message on a single line with names", func(t *testing.T) {
+		assert.Equal(t, "global Response(0x01) server_to_client cluster=OnOff(0x0006) tsn=16 endpoints=1->2 {Records=[{Identifier=OnOff(0x0000) Status=SUCCESS DataTypeValue=bool(true)} {Identifier=0x0001 Status=UNSUPPORTED_ATTRIBUTE DataTypeValue=nil}]}", cr.Describe(response).String())
+	})
+
+	t.Run("renders a message on multiple lines", func(t *testing.T) {
+		expected := `global Response(0x01) server_to_client cluster=OnOff(0x0006) tsn=16 endpoints=1->2
+  Records:
+    [0]:
+      Identifier: OnOff(0x0000)
+      Status: SUCCESS
+      DataTypeValue: bool(true)
+    [1]:
+      Identifier: 0x0001
+      Status: UNSUPPORTED_ATTRIBUTE
+      DataTypeValue: nil`
+
+		assert.Equal(t, expected, cr.Describe(response).Multiline())
+	})
+
+	t.Run("renders bitfields as flags", func(t *testing.T) {
+		message := Message{
+			FrameType:         FrameLocal,
+			Direction:         ServerToClient,
+			ClusterID:         clusterID,
+			Manufacturer:      0x1234,
+			CommandIdentifier: 0x00,
+			Command:           &Notification{Tamper: true, Alarm1: true, ZoneID: 4},
+		}
+
+		assert.Equal(t, "local Notification(0x00) server_to_client cluster=OnOff(0x0006) tsn=0 endpoints=0->0 manufacturer=0x1234 {Flags=Tamper|Alarm1 ZoneID=4}", cr.Describe(message).String())
+
+		message.Command = &Notification{}
+		assert.Contains(t, cr.Describe(message).String(), "{Flags=none ZoneID=0}")
+	})
+
+	t.Run("renders attribute data values using the preceding data type", func(t *testing.T) {
+		message := Message{
+			FrameType:         FrameGlobal,
+			CommandIdentifier: 0x02,
+			Command:           &Reporting{DataType: TypeStringOctet8, Change: &AttributeDataValue{Value: "\x01\x02"}},
+		}
+
+		assert.Contains(t, cr.Describe(message).String(), "{DataType=octstr Change=0102}")
+	})
+
+	t.Run("renders typed values", func(t *testing.T) {
+		assert.Equal(t, "uint16(non-value)", describeTypedValue(TypeUnsignedInt16, NonValue{}))
+		assert.Equal(t, `string("hi")`, describeTypedValue(TypeStringCharacter8, "hi"))
+		assert.Equal(t, "date(2020-01-02)", describeTypedValue(TypeDate, Date{Year: 120, Month: 1, DayOfMonth: 2}))
+		assert.Equal(t, "set(uint8[1 2])", describeTypedValue(TypeSet, AttributeSlice{DataType: TypeUnsignedInt8, Values: []interface{}{uint64(1), uint64(2)}}))
+		assert.Equal(t, "struct({uint8(1) EUI64(0000000000000002)})", describeTypedValue(TypeStructure, []AttributeDataTypeValue{{DataType: TypeUnsignedInt8, Value: uint64(1)}, {DataType: TypeIEEEAddress, Value: zigbee.IEEEAddress(2)}}))
+	})
+
+	t.Run("renders unknown commands", func(t *testing.T) {
+		message := Message{
+			FrameType:         FrameLocal,
+			ClusterID:         0xfc00,
+			CommandIdentifier: 0x05,
+			Command:           &UnknownCommand{Payload: []byte{0xaa, 0xbb}},
+			TrailingData:      []byte{0x01},
+		}
+
+		assert.Equal(t, "local Unknown(0x05) client_to_server cluster=0xfc00 tsn=0 endpoints=0->0 aabb trailing=01", cr.Describe(message).String())
+	})
+}
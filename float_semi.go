@@ -0,0 +1,79 @@
+package zcl
+
+import "math"
+
+/*
+ * Conversion between float32 and IEEE 754 binary16, used by ZCL for semi precision floating point. Values are rounded
+ * to nearest even, values too large for binary16 become infinity and NaN is preserved as a quiet NaN.
+ */
+
+func float32ToSemi(f float32) uint16 {
+	bits := math.Float32bits(f)
+
+	sign := uint16(bits>>16) & 0x8000
+	exponent := int((bits >> 23) & 0xff)
+	mantissa := bits & 0x7fffff
+
+	if exponent == 0xff {
+		if mantissa != 0 {
+			return sign | 0x7e00
+		}
+
+		return sign | 0x7c00
+	}
+
+	semiExponent := exponent - 127 + 15
+
+	if semiExponent >= 0x1f {
+		return sign | 0x7c00
+	}
+
+	if semiExponent <= 0 {
+		if semiExponent < -10 {
+			return sign
+		}
+
+		mantissa |= 0x800000
+		shift := uint32(14 - semiExponent)
+
+		semi := mantissa >> shift
+		remainder := mantissa & ((1 << shift) - 1)
+		halfway := uint32(1) << (shift - 1)
+
+		if remainder > halfway || (remainder == halfway && semi&1 == 1) {
+			semi++
+		}
+
+		return sign | uint16(semi)
+	}
+
+	semi := uint32(semiExponent)<<10 | mantissa>>13
+	remainder := mantissa & 0x1fff
+
+	if remainder > 0x1000 || (remainder == 0x1000 && semi&1 == 1) {
+		semi++
+	}
+
+	return sign | uint16(semi)
+}
+
+func semiToFloat32(semi uint16) float32 {
+	sign := uint32(semi&0x8000) << 16
+	exponent := uint32(semi>>10) & 0x1f
+	mantissa := uint32(semi & 0x3ff)
+
+	switch exponent {
+	case 0:
+		value := float32(mantissa) / (1 << 24)
+
+		if sign != 0 {
+			return -value
+		}
+
+		return value
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mantissa<<13)
+	default:
+		return math.Float32frombits(sign | (exponent+112)<<23 | mantissa<<13)
+	}
+}
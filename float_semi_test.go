@@ -0,0 +1,70 @@
+package zcl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func Test_float32ToSemi(t *testing.T) {
+	t.Run("converts normal values", func(t *testing.T) {
+		assert.Equal(t, uint16(0x0000), float32ToSemi(0))
+		assert.Equal(t, uint16(0x8000), float32ToSemi(float32(math.Copysign(0, -1))))
+		assert.Equal(t, uint16(0x3c00), float32ToSemi(1))
+		assert.Equal(t, uint16(0x3e00), float32ToSemi(1.5))
+		assert.Equal(t, uint16(0xc000), float32ToSemi(-2))
+		assert.Equal(t, uint16(0x7bff), float32ToSemi(65504))
+	})
+
+	t.Run("converts subnormal values", func(t *testing.T) {
+		assert.Equal(t, uint16(0x0001), float32ToSemi(float32(math.Pow(2, -24))))
+		assert.Equal(t, uint16(0x0200), float32ToSemi(float32(math.Pow(2, -15))))
+		assert.Equal(t, uint16(0x0000), float32ToSemi(float32(math.Pow(2, -26))))
+	})
+
+	t.Run("rounds to nearest even", func(t *testing.T) {
+		assert.Equal(t, uint16(0x3c00), float32ToSemi(1+float32(math.Pow(2, -11))))
+		assert.Equal(t, uint16(0x3c02), float32ToSemi(1+3*float32(math.Pow(2, -11))))
+		assert.Equal(t, uint16(0x3c01), float32ToSemi(1+float32(math.Pow(2, -11))+float32(math.Pow(2, -20))))
+	})
+
+	t.Run("converts values out of range to infinity", func(t *testing.T) {
+		assert.Equal(t, uint16(0x7c00), float32ToSemi(65520))
+		assert.Equal(t, uint16(0xfc00), float32ToSemi(-1e10))
+	})
+
+	t.Run("converts infinity and NaN", func(t *testing.T) {
+		assert.Equal(t, uint16(0x7c00), float32ToSemi(float32(math.Inf(1))))
+		assert.Equal(t, uint16(0xfc00), float32ToSemi(float32(math.Inf(-1))))
+		assert.Equal(t, uint16(0x7e00), float32ToSemi(float32(math.NaN())))
+	})
+}
+
+func Test_semiToFloat32(t *testing.T) {
+	t.Run("converts normal and subnormal values", func(t *testing.T) {
+		assert.Equal(t, float32(1), semiToFloat32(0x3c00))
+		assert.Equal(t, float32(1.5), semiToFloat32(0x3e00))
+		assert.Equal(t, float32(-2), semiToFloat32(0xc000))
+		assert.Equal(t, float32(65504), semiToFloat32(0x7bff))
+		assert.Equal(t, float32(math.Pow(2, -24)), semiToFloat32(0x0001))
+		assert.Equal(t, float32(-math.Pow(2, -15)), semiToFloat32(0x8200))
+	})
+
+	t.Run("converts infinity and NaN", func(t *testing.T) {
+		assert.True(t, math.IsInf(float64(semiToFloat32(0x7c00)), 1))
+		assert.True(t, math.IsInf(float64(semiToFloat32(0xfc00)), -1))
+		assert.True(t, math.IsNaN(float64(semiToFloat32(0x7e00))))
+	})
+
+	t.Run("all finite values round trip", func(t *testing.T) {
+		for i := 0; i <= 0xffff; i++ {
+			semi := uint16(i)
+
+			if semi&0x7c00 == 0x7c00 {
+				continue
+			}
+
+			assert.Equal(t, semi, float32ToSemi(semiToFloat32(semi)))
+		}
+	})
+}
@@ -34,15 +34,25 @@ type Header struct {
 }
 
 type Message struct {
-	FrameType           FrameType
-	Direction           Direction
-	TransactionSequence uint8
-	Manufacturer        zigbee.ManufacturerCode
-	ClusterID           zigbee.ClusterID
-	SourceEndpoint      zigbee.Endpoint
-	DestinationEndpoint zigbee.Endpoint
-	CommandIdentifier   CommandIdentifier
-	Command             interface{}
+	FrameType              FrameType
+	Direction              Direction
+	DisableDefaultResponse bool
+	ControlReserved        uint8
+	TransactionSequence    uint8
+	Manufacturer           zigbee.ManufacturerCode
+	ClusterID              zigbee.ClusterID
+	SourceEndpoint         zigbee.Endpoint
+	DestinationEndpoint    zigbee.Endpoint
+	CommandIdentifier      CommandIdentifier
+	Command                interface{}
+	TrailingData           []byte
+	ManufacturerFallback   bool
+}
+
+// UnknownCommand holds the raw payload of a command which was not found in the CommandRegistry, the identifier of the
+// command is available from the Message.
+type UnknownCommand struct {
+	Payload []byte
 }
 
 func (z Message) isManufacturerSpecific() bool {
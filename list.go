@@ -1,6 +1,11 @@
 package zcl
 
-import "github.com/shimmeringbee/zigbee"
+import (
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"strings"
+	"unicode"
+)
 
 /*
  * Zigbee Cluster List, as per Cluster Library Specification, Revision 8 (December 2918). Now branded dotdot.
@@ -278,3 +283,56 @@ var ClusterList = map[zigbee.ClusterID]string{
 	DiagnosticsId:                             "Diagnostics",
 	TouchlinkId:                               "Touchlink",
 }
+
+func normaliseClusterName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, name)
+}
+
+// ClusterIDByName finds a cluster in the ClusterList by name, the comparison ignores case, spaces and punctuation so
+// "On/Off", "OnOff" and "on_off" are all found.
+func ClusterIDByName(name string) (zigbee.ClusterID, bool) {
+	normalised := normaliseClusterName(name)
+
+	for clusterID, clusterName := range ClusterList {
+		if normaliseClusterName(clusterName) == normalised {
+			return clusterID, true
+		}
+	}
+
+	return 0, false
+}
+
+// ClusterShortName returns the name of the cluster without spaces or punctuation, such as "TemperatureMeasurement",
+// or the hexadecimal cluster ID if the cluster is unknown.
+func ClusterShortName(clusterID zigbee.ClusterID) string {
+	name, found := ClusterList[clusterID]
+
+	if !found {
+		return fmt.Sprintf("0x%04x", uint16(clusterID))
+	}
+
+	var shortName []rune
+	startOfWord := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			startOfWord = true
+			continue
+		}
+
+		if startOfWord {
+			r = unicode.ToUpper(r)
+		}
+
+		shortName = append(shortName, r)
+		startOfWord = false
+	}
+
+	return string(shortName)
+}
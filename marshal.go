@@ -12,8 +12,8 @@ func (cr *CommandRegistry) Marshal(message Message) (zigbee.ApplicationMessage,
 
 	header := Header{
 		Control: Control{
-			Reserved:               0,
-			DisableDefaultResponse: false,
+			Reserved:               message.ControlReserved,
+			DisableDefaultResponse: message.DisableDefaultResponse,
 			Direction:              message.Direction,
 			ManufacturerSpecific:   message.isManufacturerSpecific(),
 			FrameType:              message.FrameType,
@@ -22,8 +22,14 @@ func (cr *CommandRegistry) Marshal(message Message) (zigbee.ApplicationMessage,
 		TransactionSequence: message.TransactionSequence,
 	}
 
-	switch message.FrameType {
-	case FrameGlobal:
+	switch {
+	case isUnknownCommand(message.Command):
+		if message.FrameType != FrameGlobal && message.FrameType != FrameLocal {
+			return zigbee.ApplicationMessage{}, errors.New("unknown frame type encountered")
+		}
+
+		header.CommandIdentifier = message.CommandIdentifier
+	case message.FrameType == FrameGlobal:
 		commandId, err := cr.GetGlobalCommandIdentifier(message.Command)
 
 		if err != nil {
@@ -31,8 +37,8 @@ func (cr *CommandRegistry) Marshal(message Message) (zigbee.ApplicationMessage,
 		}
 
 		header.CommandIdentifier = commandId
-	case FrameLocal:
-		commandId, err := cr.GetLocalCommandIdentifier(message.ClusterID, message.Manufacturer, message.Direction, message.Command)
+	case message.FrameType == FrameLocal:
+		commandId, _, err := cr.FindLocalCommandIdentifier(message.ClusterID, message.Manufacturer, message.Direction, message.Command)
 
 		if err != nil {
 			return zigbee.ApplicationMessage{}, err
@@ -51,6 +57,12 @@ func (cr *CommandRegistry) Marshal(message Message) (zigbee.ApplicationMessage,
 		return zigbee.ApplicationMessage{}, err
 	}
 
+	for _, b := range message.TrailingData {
+		if err := bb.WriteByte(b); err != nil {
+			return zigbee.ApplicationMessage{}, err
+		}
+	}
+
 	msg := zigbee.ApplicationMessage{
 		ClusterID:           message.ClusterID,
 		SourceEndpoint:      message.SourceEndpoint,
@@ -60,3 +72,12 @@ func (cr *CommandRegistry) Marshal(message Message) (zigbee.ApplicationMessage,
 
 	return msg, nil
 }
+
+func isUnknownCommand(command interface{}) bool {
+	switch command.(type) {
+	case UnknownCommand, *UnknownCommand:
+		return true
+	}
+
+	return false
+}
@@ -99,4 +99,86 @@ func Test_Marshal(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedOut, actualOut)
 	})
+	t.Run("disable default response and reserved control bits are marshalled", func(t *testing.T) {
+		in := Message{
+			FrameType:              FrameLocal,
+			Direction:              ServerToClient,
+			DisableDefaultResponse: true,
+			ControlReserved:        0b101,
+			TransactionSequence:    0x40,
+			Manufacturer:           manufacturer,
+			ClusterID:              clusterID,
+			SourceEndpoint:         0x03,
+			DestinationEndpoint:    0x04,
+			Command: &Command{
+				FieldOne: 0xaa,
+			},
+		}
+
+		cr := NewCommandRegistry()
+		cr.RegisterLocal(clusterID, manufacturer, ServerToClient, commandID, &Command{})
+
+		expectedOut := zigbee.ApplicationMessage{
+			ClusterID:           clusterID,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			Data:                []byte{0b10111101, 0x20, 0x10, 0x40, 0xcc, 0xaa},
+		}
+
+		actualOut, err := cr.Marshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOut, actualOut)
+	})
+
+	t.Run("an unknown command marshals its raw payload", func(t *testing.T) {
+		in := Message{
+			FrameType:           FrameLocal,
+			Direction:           ClientToServer,
+			TransactionSequence: 0x40,
+			Manufacturer:        manufacturer,
+			ClusterID:           0xef00,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			CommandIdentifier:   0x02,
+			Command: &UnknownCommand{
+				Payload: []byte{0x01, 0x02, 0x03},
+			},
+		}
+
+		cr := NewCommandRegistry()
+
+		expectedOut := zigbee.ApplicationMessage{
+			ClusterID:           0xef00,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			Data:                []byte{0b00000101, 0x20, 0x10, 0x40, 0x02, 0x01, 0x02, 0x03},
+		}
+
+		actualOut, err := cr.Marshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOut, actualOut)
+	})
+
+	t.Run("trailing data is appended after the command", func(t *testing.T) {
+		in := Message{
+			FrameType:           FrameGlobal,
+			Direction:           ClientToServer,
+			TransactionSequence: 0x40,
+			ClusterID:           clusterID,
+			Command: &Command{
+				FieldOne: 0xaa,
+			},
+			TrailingData: []byte{0xbb, 0xcc},
+		}
+
+		cr := NewCommandRegistry()
+		cr.RegisterGlobal(commandID, &Command{})
+
+		actualOut, err := cr.Marshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0b00000000, 0x40, 0xcc, 0xaa, 0xbb, 0xcc}, actualOut.Data)
+	})
 }
@@ -0,0 +1,228 @@
+package zcl
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"reflect"
+)
+
+var ErrCommandNameMismatch = errors.New("command name does not match registered command")
+
+type messageJSON struct {
+	FrameType              string                  `json:"frame_type"`
+	Direction              string                  `json:"direction"`
+	DisableDefaultResponse bool                    `json:"disable_default_response,omitempty"`
+	ControlReserved        uint8                   `json:"control_reserved,omitempty"`
+	TransactionSequence    uint8                   `json:"transaction_sequence"`
+	Manufacturer           zigbee.ManufacturerCode `json:"manufacturer"`
+	ClusterID              zigbee.ClusterID        `json:"cluster"`
+	ClusterName            string                  `json:"cluster_name,omitempty"`
+	SourceEndpoint         zigbee.Endpoint         `json:"source_endpoint"`
+	DestinationEndpoint    zigbee.Endpoint         `json:"destination_endpoint"`
+	CommandIdentifier      CommandIdentifier       `json:"command_identifier"`
+	Command                string                  `json:"command,omitempty"`
+	Payload                json.RawMessage         `json:"payload"`
+	TrailingData           string                  `json:"trailing_data,omitempty"`
+	ManufacturerFallback   bool                    `json:"manufacturer_fallback,omitempty"`
+}
+
+var frameTypeNames = map[FrameType]string{
+	FrameGlobal: "global",
+	FrameLocal:  "local",
+}
+
+var directionNames = map[Direction]string{
+	ClientToServer: "client_to_server",
+	ServerToClient: "server_to_client",
+}
+
+// MarshalMessageJSON encodes a Message as JSON, the command is recorded by identifier and registered name alongside
+// its payload so that it can be decoded by UnmarshalMessageJSON. Commands which are not registered must be provided
+// as an UnknownCommand, their payload is encoded as a hexadecimal string.
+func (cr *CommandRegistry) MarshalMessageJSON(message Message) ([]byte, error) {
+	frameType, found := frameTypeNames[message.FrameType]
+
+	if !found {
+		return nil, errors.New("unknown frame type encountered")
+	}
+
+	direction, found := directionNames[message.Direction]
+
+	if !found {
+		return nil, fmt.Errorf("unknown direction encountered: %d", message.Direction)
+	}
+
+	encoded := messageJSON{
+		FrameType:              frameType,
+		Direction:              direction,
+		DisableDefaultResponse: message.DisableDefaultResponse,
+		ControlReserved:        message.ControlReserved,
+		TransactionSequence:    message.TransactionSequence,
+		Manufacturer:           message.Manufacturer,
+		ClusterID:              message.ClusterID,
+		ClusterName:            ClusterShortName(message.ClusterID),
+		SourceEndpoint:         message.SourceEndpoint,
+		DestinationEndpoint:    message.DestinationEndpoint,
+		TrailingData:           hex.EncodeToString(message.TrailingData),
+		ManufacturerFallback:   message.ManufacturerFallback,
+	}
+
+	var err error
+
+	switch command := message.Command.(type) {
+	case UnknownCommand:
+		encoded.CommandIdentifier = message.CommandIdentifier
+		encoded.Payload, err = json.Marshal(hex.EncodeToString(command.Payload))
+	case *UnknownCommand:
+		encoded.CommandIdentifier = message.CommandIdentifier
+		encoded.Payload, err = json.Marshal(hex.EncodeToString(command.Payload))
+	default:
+		if message.FrameType == FrameGlobal {
+			encoded.CommandIdentifier, err = cr.GetGlobalCommandIdentifier(message.Command)
+
+			if err == nil {
+				encoded.Command, _ = cr.GlobalCommandName(encoded.CommandIdentifier)
+			}
+		} else {
+			var matchedManufacturer zigbee.ManufacturerCode
+			encoded.CommandIdentifier, matchedManufacturer, err = cr.FindLocalCommandIdentifier(message.ClusterID, message.Manufacturer, message.Direction, message.Command)
+
+			if err == nil {
+				encoded.Command, _ = cr.LocalCommandName(message.ClusterID, matchedManufacturer, message.Direction, encoded.CommandIdentifier)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		encoded.Payload, err = json.Marshal(message.Command)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalMessageJSON decodes a Message encoded by MarshalMessageJSON, the command is constructed from the
+// CommandRegistry and validated in the same manner as Unmarshal. A message with no command name whose identifier is
+// not registered is decoded as an UnknownCommand.
+func (cr *CommandRegistry) UnmarshalMessageJSON(data []byte) (Message, error) {
+	encoded := messageJSON{}
+
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return Message{}, err
+	}
+
+	message := Message{
+		DisableDefaultResponse: encoded.DisableDefaultResponse,
+		ControlReserved:        encoded.ControlReserved,
+		TransactionSequence:    encoded.TransactionSequence,
+		Manufacturer:           encoded.Manufacturer,
+		ClusterID:              encoded.ClusterID,
+		SourceEndpoint:         encoded.SourceEndpoint,
+		DestinationEndpoint:    encoded.DestinationEndpoint,
+		CommandIdentifier:      encoded.CommandIdentifier,
+	}
+
+	var found bool
+
+	if message.FrameType, found = frameTypeByName(encoded.FrameType); !found {
+		return Message{}, fmt.Errorf("unknown frame type encountered: %q", encoded.FrameType)
+	}
+
+	if message.Direction, found = directionByName(encoded.Direction); !found {
+		return Message{}, fmt.Errorf("unknown direction encountered: %q", encoded.Direction)
+	}
+
+	if encoded.TrailingData != "" {
+		trailingData, err := hex.DecodeString(encoded.TrailingData)
+
+		if err != nil {
+			return Message{}, fmt.Errorf("invalid trailing data: %w", err)
+		}
+
+		message.TrailingData = trailingData
+	}
+
+	var registration *commandRegistration
+	var err error
+
+	if message.FrameType == FrameGlobal {
+		registration, err = cr.globalRegistration(message.CommandIdentifier)
+	} else {
+		var matchedManufacturer zigbee.ManufacturerCode
+		registration, matchedManufacturer, err = cr.findLocalRegistration(message.ClusterID, message.Manufacturer, message.Direction, message.CommandIdentifier)
+		message.ManufacturerFallback = err == nil && matchedManufacturer != message.Manufacturer
+	}
+
+	if err != nil {
+		if encoded.Command != "" {
+			return Message{}, fmt.Errorf("unknown ZCL %s command %q with identifier %d", encoded.FrameType, encoded.Command, message.CommandIdentifier)
+		}
+
+		var payload string
+
+		if err := json.Unmarshal(encoded.Payload, &payload); err != nil {
+			return Message{}, fmt.Errorf("invalid unknown command payload: %w", err)
+		}
+
+		raw, err := hex.DecodeString(payload)
+
+		if err != nil {
+			return Message{}, fmt.Errorf("invalid unknown command payload: %w", err)
+		}
+
+		message.Command = &UnknownCommand{Payload: raw}
+		return message, nil
+	}
+
+	if encoded.Command != "" && encoded.Command != registration.name() {
+		return Message{}, fmt.Errorf("%w: %q is registered as %q", ErrCommandNameMismatch, encoded.Command, registration.name())
+	}
+
+	command := registration.definition.New()
+
+	if len(encoded.Payload) > 0 {
+		if err := json.Unmarshal(encoded.Payload, command); err != nil {
+			return Message{}, err
+		}
+	}
+
+	if err := resolveAttributeDataValues(reflect.ValueOf(command)); err != nil {
+		return Message{}, err
+	}
+
+	if err := registration.validate(command); err != nil {
+		return Message{}, fmt.Errorf("ZCL command identifier %d failed validation: %w", message.CommandIdentifier, err)
+	}
+
+	message.Command = command
+
+	return message, nil
+}
+
+func frameTypeByName(name string) (FrameType, bool) {
+	for frameType, frameTypeName := range frameTypeNames {
+		if frameTypeName == name {
+			return frameType, true
+		}
+	}
+
+	return 0, false
+}
+
+func directionByName(name string) (Direction, bool) {
+	for direction, directionName := range directionNames {
+		if directionName == name {
+			return direction, true
+		}
+	}
+
+	return 0, false
+}
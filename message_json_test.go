@@ -0,0 +1,139 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_MessageJSON(t *testing.T) {
+	type Command struct {
+		FieldOne uint8
+		Value    *AttributeDataTypeValue
+	}
+
+	type ReportingCommand struct {
+		DataType AttributeDataType
+		Change   *AttributeDataValue
+	}
+
+	clusterID := zigbee.ClusterID(0x0006)
+	commandID := CommandIdentifier(0xcc)
+	manufacturer := zigbee.ManufacturerCode(0x1020)
+
+	cr := NewCommandRegistry()
+	cr.RegisterGlobal(commandID, &Command{})
+	cr.RegisterGlobal(commandID+1, &ReportingCommand{})
+	cr.RegisterLocal(clusterID, manufacturer, ServerToClient, commandID, &Command{})
+
+	t.Run("round trips a global message", func(t *testing.T) {
+		expected := Message{
+			FrameType:           FrameGlobal,
+			Direction:           ClientToServer,
+			TransactionSequence: 0x40,
+			ClusterID:           clusterID,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			CommandIdentifier:   commandID,
+			Command: &Command{
+				FieldOne: 0xaa,
+				Value:    &AttributeDataTypeValue{DataType: TypeIEEEAddress, Value: zigbee.IEEEAddress(0x0102030405060708)},
+			},
+			TrailingData: []byte{0x01, 0x02},
+		}
+
+		data, err := cr.MarshalMessageJSON(expected)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"frame_type":"global","direction":"client_to_server","transaction_sequence":64,"manufacturer":0,"cluster":6,"cluster_name":"OnOff","source_endpoint":3,"destination_endpoint":4,"command_identifier":204,"command":"Command","payload":{"FieldOne":170,"Value":{"type":"EUI64","value":72623859790382856}},"trailing_data":"0102"}`, string(data))
+
+		actual, err := cr.UnmarshalMessageJSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("round trips a local message with manufacturer", func(t *testing.T) {
+		expected := Message{
+			FrameType:           FrameLocal,
+			Direction:           ServerToClient,
+			TransactionSequence: 0x41,
+			Manufacturer:        manufacturer,
+			ClusterID:           clusterID,
+			CommandIdentifier:   commandID,
+			Command: &Command{
+				Value: &AttributeDataTypeValue{DataType: TypeSet, Value: AttributeSlice{DataType: TypeUnsignedInt8, Values: []interface{}{uint64(1), uint64(2)}}},
+			},
+		}
+
+		data, err := cr.MarshalMessageJSON(expected)
+		assert.NoError(t, err)
+
+		actual, err := cr.UnmarshalMessageJSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("resolves attribute data values using the preceding data type", func(t *testing.T) {
+		expected := Message{
+			FrameType:         FrameGlobal,
+			Direction:         ClientToServer,
+			CommandIdentifier: commandID + 1,
+			Command: &ReportingCommand{
+				DataType: TypeSignedInt16,
+				Change:   &AttributeDataValue{Value: int64(-10)},
+			},
+		}
+
+		data, err := cr.MarshalMessageJSON(expected)
+		assert.NoError(t, err)
+
+		actual, err := cr.UnmarshalMessageJSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("round trips unknown commands", func(t *testing.T) {
+		expected := Message{
+			FrameType:         FrameLocal,
+			Direction:         ClientToServer,
+			ClusterID:         clusterID,
+			CommandIdentifier: 0x10,
+			Command:           &UnknownCommand{Payload: []byte{0xde, 0xad}},
+		}
+
+		data, err := cr.MarshalMessageJSON(expected)
+		assert.NoError(t, err)
+
+		actual, err := cr.UnmarshalMessageJSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("errors marshalling unregistered commands", func(t *testing.T) {
+		_, err := cr.MarshalMessageJSON(Message{FrameType: FrameGlobal, Command: &struct{}{}})
+		assert.Error(t, err)
+	})
+
+	t.Run("errors if the command name does not match the registration", func(t *testing.T) {
+		_, err := cr.UnmarshalMessageJSON([]byte(`{"frame_type":"global","direction":"client_to_server","command_identifier":204,"command":"Other","payload":{}}`))
+		assert.True(t, errors.Is(err, ErrCommandNameMismatch))
+	})
+
+	t.Run("errors if a named command is not registered", func(t *testing.T) {
+		_, err := cr.UnmarshalMessageJSON([]byte(`{"frame_type":"global","direction":"client_to_server","command_identifier":1,"command":"Command","payload":{}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("validates decoded commands", func(t *testing.T) {
+		validated := NewCommandRegistry()
+		validated.MustRegisterGlobalDefinition(commandID, CommandDefinition{
+			New: func() interface{} { return &Command{} },
+			Validate: func(command interface{}) error {
+				return errors.New("invalid")
+			},
+		})
+
+		_, err := validated.UnmarshalMessageJSON([]byte(`{"frame_type":"global","direction":"client_to_server","command_identifier":204,"payload":{"FieldOne":1}}`))
+		assert.Error(t, err)
+	})
+}
@@ -1,45 +1,361 @@
 package zcl
 
 import (
+	"errors"
 	"fmt"
 	"github.com/shimmeringbee/zigbee"
 	"reflect"
+	"sort"
+	"sync"
+)
+
+var ErrRegistrationConflict = errors.New("conflicting command registration")
+
+// ConflictPolicy determines how a registration which conflicts with an existing one is handled. The Must variants of
+// the registration functions panic on any error returned, so respect the policy in the same way.
+type ConflictPolicy uint8
+
+const (
+	// ConflictReplace causes a conflicting registration to replace the registrations it conflicts with, this is the
+	// default.
+	ConflictReplace ConflictPolicy = iota
+	// ConflictReturnError causes a conflicting registration to be rejected, returning ErrRegistrationConflict.
+	ConflictReturnError
+	// ConflictPanic causes a conflicting registration to panic, for use where registrations are static.
+	ConflictPanic
+)
+
+type ManufacturerLookup uint8
+
+const (
+	// ManufacturerExact requires local commands to be registered against the exact manufacturer in the frame.
+	ManufacturerExact ManufacturerLookup = iota
+	// ManufacturerFallback attempts the manufacturer in the frame first, and then falls back to commands registered
+	// against zigbee.NoManufacturer.
+	ManufacturerFallback
 )
 
 type CommandRegistry struct {
-	globalIdentifierToInterface map[CommandIdentifier]interface{}
+	mutex          *sync.RWMutex
+	conflictPolicy ConflictPolicy
+
+	manufacturerLookup        ManufacturerLookup
+	clusterManufacturerLookup map[zigbee.ClusterID]ManufacturerLookup
+
+	globalIdentifierToInterface map[CommandIdentifier]*commandRegistration
 	globalInterfaceToIdentifier map[reflect.Type]CommandIdentifier
 
-	localIdentifierToInterface map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]interface{}
+	localIdentifierToInterface map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]*commandRegistration
 	localInterfaceToIdentifier map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[reflect.Type]CommandIdentifier
+
+	localResponses        map[localCommandKey]CommandIdentifier
+	localResponseCommands map[localCommandKey]bool
+
+	attributes map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[AttributeID]AttributeDefinition
+}
+
+// CommandDefinition describes how a command is constructed and validated, New must return a pointer to a new
+// instance of the command each time it is called. Validate is optional and is called after the command has been
+// unmarshalled. Name is optional, if not provided the name of the commands type is used.
+type CommandDefinition struct {
+	Name     string
+	New      func() interface{}
+	Validate func(command interface{}) error
+}
+
+type commandRegistration struct {
+	definition    CommandDefinition
+	reflectedType reflect.Type
+}
+
+func newCommandRegistration(definition CommandDefinition) (*commandRegistration, error) {
+	if definition.New == nil {
+		return nil, errors.New("command definition does not provide a constructor")
+	}
+
+	reflectedType := reflect.TypeOf(definition.New())
+
+	if reflectedType == nil || reflectedType.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("command definition constructor must return a pointer, got %v", reflectedType)
+	}
+
+	return &commandRegistration{definition: definition, reflectedType: reflectedType}, nil
+}
+
+func prototypeDefinition(command interface{}) CommandDefinition {
+	reflectedType := reflect.TypeOf(command)
+
+	if reflectedType == nil || reflectedType.Kind() != reflect.Ptr {
+		return CommandDefinition{New: func() interface{} { return command }}
+	}
+
+	return CommandDefinition{
+		New: func() interface{} {
+			return reflect.New(reflectedType.Elem()).Interface()
+		},
+	}
+}
+
+func (r *commandRegistration) name() string {
+	if r.definition.Name != "" {
+		return r.definition.Name
+	}
+
+	return r.reflectedType.Elem().Name()
+}
+
+func (r *commandRegistration) validate(command interface{}) error {
+	if r.definition.Validate == nil {
+		return nil
+	}
+
+	return r.definition.Validate(command)
 }
 
 func NewCommandRegistry() *CommandRegistry {
 	return &CommandRegistry{
-		globalIdentifierToInterface: make(map[CommandIdentifier]interface{}),
+		mutex:                       &sync.RWMutex{},
+		clusterManufacturerLookup:   make(map[zigbee.ClusterID]ManufacturerLookup),
+		globalIdentifierToInterface: make(map[CommandIdentifier]*commandRegistration),
 		globalInterfaceToIdentifier: make(map[reflect.Type]CommandIdentifier),
-		localIdentifierToInterface:  make(map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]interface{}),
+		localIdentifierToInterface:  make(map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]*commandRegistration),
 		localInterfaceToIdentifier:  make(map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[Direction]map[reflect.Type]CommandIdentifier),
+		localResponses:              make(map[localCommandKey]CommandIdentifier),
+		localResponseCommands:       make(map[localCommandKey]bool),
+		attributes:                  make(map[zigbee.ClusterID]map[zigbee.ManufacturerCode]map[AttributeID]AttributeDefinition),
 	}
 }
 
-func (cr *CommandRegistry) RegisterGlobal(identifier CommandIdentifier, command interface{}) {
-	cr.globalIdentifierToInterface[identifier] = command
-	cr.globalInterfaceToIdentifier[reflect.TypeOf(command)] = identifier
+func (cr *CommandRegistry) SetConflictPolicy(policy ConflictPolicy) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.conflictPolicy = policy
+}
+
+// SetManufacturerLookup sets the default manufacturer lookup used when unmarshalling and marshalling local commands.
+func (cr *CommandRegistry) SetManufacturerLookup(lookup ManufacturerLookup) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.manufacturerLookup = lookup
+}
+
+// SetClusterManufacturerLookup overrides the manufacturer lookup for a single cluster.
+func (cr *CommandRegistry) SetClusterManufacturerLookup(clusterID zigbee.ClusterID, lookup ManufacturerLookup) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.clusterManufacturerLookup[clusterID] = lookup
+}
+
+func (cr *CommandRegistry) manufacturerFallback(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode) bool {
+	if manufacturer == zigbee.NoManufacturer {
+		return false
+	}
+
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	lookup, found := cr.clusterManufacturerLookup[clusterID]
+
+	if !found {
+		lookup = cr.manufacturerLookup
+	}
+
+	return lookup == ManufacturerFallback
+}
+
+// FindLocalCommand returns a new instance of a local command using the manufacturer lookup for the cluster, the
+// manufacturer of the registration which matched is also returned.
+func (cr *CommandRegistry) FindLocalCommand(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) (interface{}, zigbee.ManufacturerCode, error) {
+	registration, matchedManufacturer, err := cr.findLocalRegistration(clusterID, manufacturer, direction, identifier)
+
+	if err != nil {
+		return nil, manufacturer, err
+	}
+
+	return registration.definition.New(), matchedManufacturer, nil
+}
+
+func (cr *CommandRegistry) findLocalRegistration(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) (*commandRegistration, zigbee.ManufacturerCode, error) {
+	registration, err := cr.localRegistration(clusterID, manufacturer, direction, identifier)
+
+	if err != nil && cr.manufacturerFallback(clusterID, manufacturer) {
+		if registration, fallbackErr := cr.localRegistration(clusterID, zigbee.NoManufacturer, direction, identifier); fallbackErr == nil {
+			return registration, zigbee.NoManufacturer, nil
+		}
+	}
+
+	return registration, manufacturer, err
+}
+
+// FindLocalCommandIdentifier returns the identifier of a local command using the manufacturer lookup for the cluster,
+// the manufacturer of the registration which matched is also returned.
+func (cr *CommandRegistry) FindLocalCommandIdentifier(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, command interface{}) (CommandIdentifier, zigbee.ManufacturerCode, error) {
+	identifier, err := cr.GetLocalCommandIdentifier(clusterID, manufacturer, direction, command)
+
+	if err != nil && cr.manufacturerFallback(clusterID, manufacturer) {
+		if identifier, fallbackErr := cr.GetLocalCommandIdentifier(clusterID, zigbee.NoManufacturer, direction, command); fallbackErr == nil {
+			return identifier, zigbee.NoManufacturer, nil
+		}
+	}
+
+	return identifier, manufacturer, err
+}
+
+// conflict applies the ConflictPolicy to a conflicting registration, if no error is returned the registration should
+// replace those it conflicts with.
+func (cr *CommandRegistry) conflict(err error) error {
+	if cr.conflictPolicy == ConflictReplace {
+		return nil
+	}
+
+	return cr.reject(err)
+}
+
+// reject returns or panics with an error depending on the ConflictPolicy, for registrations which can not be resolved
+// by replacement.
+func (cr *CommandRegistry) reject(err error) error {
+	if cr.conflictPolicy == ConflictPanic {
+		panic(err)
+	}
+
+	return err
+}
+
+func checkConflict(id2Int map[CommandIdentifier]*commandRegistration, int2Id map[reflect.Type]CommandIdentifier, identifier CommandIdentifier, registration *commandRegistration) (bool, error) {
+	reflectedType := registration.reflectedType
+
+	existing, identifierFound := id2Int[identifier]
+	existingIdentifier, typeFound := int2Id[reflectedType]
+
+	if identifierFound && existing.reflectedType != reflectedType {
+		return false, fmt.Errorf("%w: identifier %d already registered to %s, can not register %s", ErrRegistrationConflict, identifier, existing.reflectedType, reflectedType)
+	}
+
+	if typeFound && existingIdentifier != identifier {
+		return false, fmt.Errorf("%w: %s already registered to identifier %d, can not register to %d", ErrRegistrationConflict, reflectedType, existingIdentifier, identifier)
+	}
+
+	if identifierFound && !sameDefinition(existing.definition, registration.definition) {
+		return false, fmt.Errorf("%w: %s already registered to identifier %d with a different definition", ErrRegistrationConflict, reflectedType, identifier)
+	}
+
+	return identifierFound && typeFound, nil
+}
+
+// removeConflicting removes the registrations which conflict with registering the type to the identifier, the
+// identifier the type was previously registered to is returned if it differs.
+func removeConflicting(id2Int map[CommandIdentifier]*commandRegistration, int2Id map[reflect.Type]CommandIdentifier, identifier CommandIdentifier, reflectedType reflect.Type) (CommandIdentifier, bool) {
+	if existing, found := id2Int[identifier]; found && int2Id[existing.reflectedType] == identifier {
+		delete(int2Id, existing.reflectedType)
+	}
+
+	existingIdentifier, found := int2Id[reflectedType]
+
+	if !found || existingIdentifier == identifier {
+		return 0, false
+	}
+
+	delete(id2Int, existingIdentifier)
+	delete(int2Id, reflectedType)
+
+	return existingIdentifier, true
+}
+
+func sameDefinition(a CommandDefinition, b CommandDefinition) bool {
+	return a.Name == b.Name && sameFunc(a.New, b.New) && sameFunc(a.Validate, b.Validate)
+}
+
+// sameFunc compares functions by their code, closures created by the same function literal are considered the same.
+func sameFunc(a interface{}, b interface{}) bool {
+	aValue := reflect.ValueOf(a)
+	bValue := reflect.ValueOf(b)
+
+	if aValue.IsNil() || bValue.IsNil() {
+		return aValue.IsNil() == bValue.IsNil()
+	}
+
+	return aValue.Pointer() == bValue.Pointer()
+}
+
+// RegisterGlobal registers a global command, registering the same identifier and type again is permitted. An
+// identifier or type which is already registered to another, or the same type with a different definition, is a
+// conflict and is replaced, returns ErrRegistrationConflict or panics depending on the ConflictPolicy.
+func (cr *CommandRegistry) RegisterGlobal(identifier CommandIdentifier, command interface{}) error {
+	return cr.RegisterGlobalDefinition(identifier, prototypeDefinition(command))
+}
+
+// RegisterGlobalDefinition registers a global command using a CommandDefinition, rather than a prototype instance.
+func (cr *CommandRegistry) RegisterGlobalDefinition(identifier CommandIdentifier, definition CommandDefinition) error {
+	registration, err := newCommandRegistration(definition)
+
+	if err != nil {
+		return err
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	if duplicate, err := checkConflict(cr.globalIdentifierToInterface, cr.globalInterfaceToIdentifier, identifier, registration); err != nil {
+		if err := cr.conflict(fmt.Errorf("global command: %w", err)); err != nil {
+			return err
+		}
+
+		removeConflicting(cr.globalIdentifierToInterface, cr.globalInterfaceToIdentifier, identifier, registration.reflectedType)
+	} else if duplicate {
+		return nil
+	}
+
+	cr.globalIdentifierToInterface[identifier] = registration
+	cr.globalInterfaceToIdentifier[registration.reflectedType] = identifier
+
+	return nil
+}
+
+func (cr *CommandRegistry) MustRegisterGlobal(identifier CommandIdentifier, command interface{}) {
+	if err := cr.RegisterGlobal(identifier, command); err != nil {
+		panic(err)
+	}
+}
+
+func (cr *CommandRegistry) MustRegisterGlobalDefinition(identifier CommandIdentifier, definition CommandDefinition) {
+	if err := cr.RegisterGlobalDefinition(identifier, definition); err != nil {
+		panic(err)
+	}
 }
 
 func (cr *CommandRegistry) GetGlobalCommand(identifier CommandIdentifier) (interface{}, error) {
-	sampleObj, found := cr.globalIdentifierToInterface[identifier]
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	registration, found := cr.globalIdentifierToInterface[identifier]
 
 	if found {
-		reflectedType := reflect.TypeOf(sampleObj).Elem()
-		return reflect.New(reflectedType).Interface(), nil
+		return registration.definition.New(), nil
 	} else {
 		return 0, fmt.Errorf("could not find global command for identifier: %d", identifier)
 	}
 }
 
+func (cr *CommandRegistry) globalRegistration(identifier CommandIdentifier) (*commandRegistration, error) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	registration, found := cr.globalIdentifierToInterface[identifier]
+
+	if !found {
+		return nil, fmt.Errorf("could not find global command for identifier: %d", identifier)
+	}
+
+	return registration, nil
+}
+
 func (cr *CommandRegistry) GetGlobalCommandIdentifier(command interface{}) (CommandIdentifier, error) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
 	reflectedType := reflect.TypeOf(command)
 	identifier, found := cr.globalInterfaceToIdentifier[reflectedType]
 
@@ -50,12 +366,43 @@ func (cr *CommandRegistry) GetGlobalCommandIdentifier(command interface{}) (Comm
 	}
 }
 
-func (cr *CommandRegistry) RegisterLocal(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier, command interface{}) {
+// RegisterLocal registers a cluster specific command, see RegisterGlobal for the handling of conflicts.
+func (cr *CommandRegistry) RegisterLocal(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier, command interface{}) error {
+	return cr.RegisterLocalDefinition(clusterID, manufacturer, direction, identifier, prototypeDefinition(command))
+}
+
+// RegisterLocalDefinition registers a cluster specific command using a CommandDefinition, rather than a prototype
+// instance.
+func (cr *CommandRegistry) RegisterLocalDefinition(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier, definition CommandDefinition) error {
+	registration, err := newCommandRegistration(definition)
+
+	if err != nil {
+		return err
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	id2Int := cr.localIdentifierToInterface[clusterID][manufacturer][direction]
+	int2Id := cr.localInterfaceToIdentifier[clusterID][manufacturer][direction]
+
+	if duplicate, err := checkConflict(id2Int, int2Id, identifier, registration); err != nil {
+		if err := cr.conflict(fmt.Errorf("local command for cluster %d manufacturer %d direction %d: %w", clusterID, manufacturer, direction, err)); err != nil {
+			return err
+		}
+
+		if removed, found := removeConflicting(id2Int, int2Id, identifier, registration.reflectedType); found {
+			cr.removeLocalResponses(localCommandKey{clusterID: clusterID, manufacturer: manufacturer, direction: direction, identifier: removed})
+		}
+	} else if duplicate {
+		return nil
+	}
+
 	clusterId2IntResult, clusterId2IntFound := cr.localIdentifierToInterface[clusterID]
 	clusterInt2IdResult, clusterInt2IdFound := cr.localInterfaceToIdentifier[clusterID]
 
 	if !clusterId2IntFound {
-		clusterId2IntResult = make(map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]interface{})
+		clusterId2IntResult = make(map[zigbee.ManufacturerCode]map[Direction]map[CommandIdentifier]*commandRegistration)
 		cr.localIdentifierToInterface[clusterID] = clusterId2IntResult
 	}
 
@@ -68,7 +415,7 @@ func (cr *CommandRegistry) RegisterLocal(clusterID zigbee.ClusterID, manufacture
 	manufacturerInt2IdResult, manufacturerInt2IdFound := clusterInt2IdResult[manufacturer]
 
 	if !manufacturerId2IntFound {
-		manufacturerId2IntResult = make(map[Direction]map[CommandIdentifier]interface{})
+		manufacturerId2IntResult = make(map[Direction]map[CommandIdentifier]*commandRegistration)
 		clusterId2IntResult[manufacturer] = manufacturerId2IntResult
 	}
 
@@ -81,7 +428,7 @@ func (cr *CommandRegistry) RegisterLocal(clusterID zigbee.ClusterID, manufacture
 	directionInt2IdResult, directionInt2IdFound := manufacturerInt2IdResult[direction]
 
 	if !directionId2IntFound {
-		directionId2IntResult = make(map[CommandIdentifier]interface{})
+		directionId2IntResult = make(map[CommandIdentifier]*commandRegistration)
 		manufacturerId2IntResult[direction] = directionId2IntResult
 	}
 
@@ -90,11 +437,38 @@ func (cr *CommandRegistry) RegisterLocal(clusterID zigbee.ClusterID, manufacture
 		manufacturerInt2IdResult[direction] = directionInt2IdResult
 	}
 
-	directionId2IntResult[identifier] = command
-	directionInt2IdResult[reflect.TypeOf(command)] = identifier
+	directionId2IntResult[identifier] = registration
+	directionInt2IdResult[registration.reflectedType] = identifier
+
+	return nil
+}
+
+func (cr *CommandRegistry) MustRegisterLocal(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier, command interface{}) {
+	if err := cr.RegisterLocal(clusterID, manufacturer, direction, identifier, command); err != nil {
+		panic(err)
+	}
+}
+
+func (cr *CommandRegistry) MustRegisterLocalDefinition(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier, definition CommandDefinition) {
+	if err := cr.RegisterLocalDefinition(clusterID, manufacturer, direction, identifier, definition); err != nil {
+		panic(err)
+	}
 }
 
 func (cr *CommandRegistry) GetLocalCommand(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) (interface{}, error) {
+	registration, err := cr.localRegistration(clusterID, manufacturer, direction, identifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return registration.definition.New(), nil
+}
+
+func (cr *CommandRegistry) localRegistration(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) (*commandRegistration, error) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
 	clusterResult, clusterFound := cr.localIdentifierToInterface[clusterID]
 
 	if !clusterFound {
@@ -113,17 +487,19 @@ func (cr *CommandRegistry) GetLocalCommand(clusterID zigbee.ClusterID, manufactu
 		return nil, fmt.Errorf("could not find local command for: cluster %d manufacturer %d identifier %d direction %d", clusterID, manufacturer, identifier, direction)
 	}
 
-	interfaceResult, interfaceFound := directionResult[identifier]
+	registration, registrationFound := directionResult[identifier]
 
-	if !interfaceFound {
+	if !registrationFound {
 		return nil, fmt.Errorf("could not find local command for: cluster %d manufacturer %d identifier %d", clusterID, manufacturer, identifier)
 	}
 
-	reflectedType := reflect.TypeOf(interfaceResult).Elem()
-	return reflect.New(reflectedType).Interface(), nil
+	return registration, nil
 }
 
 func (cr *CommandRegistry) GetLocalCommandIdentifier(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, command interface{}) (CommandIdentifier, error) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
 	reflectedType := reflect.TypeOf(command)
 	clusterResult, clusterFound := cr.localInterfaceToIdentifier[clusterID]
 
@@ -151,3 +527,179 @@ func (cr *CommandRegistry) GetLocalCommandIdentifier(clusterID zigbee.ClusterID,
 
 	return identifierResult, nil
 }
+
+func (cr *CommandRegistry) UnregisterGlobal(identifier CommandIdentifier) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	registration, found := cr.globalIdentifierToInterface[identifier]
+
+	if !found {
+		return
+	}
+
+	delete(cr.globalIdentifierToInterface, identifier)
+
+	if cr.globalInterfaceToIdentifier[registration.reflectedType] == identifier {
+		delete(cr.globalInterfaceToIdentifier, registration.reflectedType)
+	}
+}
+
+func (cr *CommandRegistry) UnregisterLocal(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	id2Int := cr.localIdentifierToInterface[clusterID][manufacturer][direction]
+	int2Id := cr.localInterfaceToIdentifier[clusterID][manufacturer][direction]
+
+	registration, found := id2Int[identifier]
+
+	if !found {
+		return
+	}
+
+	delete(id2Int, identifier)
+
+	cr.removeLocalResponses(localCommandKey{clusterID: clusterID, manufacturer: manufacturer, direction: direction, identifier: identifier})
+
+	if int2Id[registration.reflectedType] == identifier {
+		delete(int2Id, registration.reflectedType)
+	}
+
+	if len(id2Int) == 0 {
+		delete(cr.localIdentifierToInterface[clusterID][manufacturer], direction)
+		delete(cr.localInterfaceToIdentifier[clusterID][manufacturer], direction)
+	}
+
+	if len(cr.localIdentifierToInterface[clusterID][manufacturer]) == 0 {
+		delete(cr.localIdentifierToInterface[clusterID], manufacturer)
+		delete(cr.localInterfaceToIdentifier[clusterID], manufacturer)
+	}
+
+	if len(cr.localIdentifierToInterface[clusterID]) == 0 {
+		delete(cr.localIdentifierToInterface, clusterID)
+		delete(cr.localInterfaceToIdentifier, clusterID)
+	}
+}
+
+type RegisteredCommand struct {
+	Identifier CommandIdentifier
+	Name       string
+	Type       reflect.Type
+	Definition CommandDefinition
+}
+
+func (cr *CommandRegistry) GlobalCommands() []RegisteredCommand {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return registeredCommands(cr.globalIdentifierToInterface)
+}
+
+func (cr *CommandRegistry) LocalClusters() []zigbee.ClusterID {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var clusters []zigbee.ClusterID
+
+	for clusterID := range cr.localIdentifierToInterface {
+		clusters = append(clusters, clusterID)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i] < clusters[j] })
+
+	return clusters
+}
+
+func (cr *CommandRegistry) LocalManufacturers(clusterID zigbee.ClusterID) []zigbee.ManufacturerCode {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var manufacturers []zigbee.ManufacturerCode
+
+	for manufacturer := range cr.localIdentifierToInterface[clusterID] {
+		manufacturers = append(manufacturers, manufacturer)
+	}
+
+	sort.Slice(manufacturers, func(i, j int) bool { return manufacturers[i] < manufacturers[j] })
+
+	return manufacturers
+}
+
+func (cr *CommandRegistry) LocalDirections(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode) []Direction {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var directions []Direction
+
+	for direction := range cr.localIdentifierToInterface[clusterID][manufacturer] {
+		directions = append(directions, direction)
+	}
+
+	sort.Slice(directions, func(i, j int) bool { return directions[i] < directions[j] })
+
+	return directions
+}
+
+func (cr *CommandRegistry) LocalCommands(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction) []RegisteredCommand {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return registeredCommands(cr.localIdentifierToInterface[clusterID][manufacturer][direction])
+}
+
+func registeredCommands(commands map[CommandIdentifier]*commandRegistration) []RegisteredCommand {
+	var registered []RegisteredCommand
+
+	for identifier, registration := range commands {
+		registered = append(registered, RegisteredCommand{Identifier: identifier, Name: registration.name(), Type: registration.reflectedType, Definition: registration.definition})
+	}
+
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Identifier < registered[j].Identifier })
+
+	return registered
+}
+
+func (cr *CommandRegistry) GlobalCommandName(identifier CommandIdentifier) (string, bool) {
+	registration, err := cr.globalRegistration(identifier)
+
+	if err != nil {
+		return "", false
+	}
+
+	return registration.name(), true
+}
+
+func (cr *CommandRegistry) GlobalCommandIdentifierByName(name string) (CommandIdentifier, bool) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return commandIdentifierByName(cr.globalIdentifierToInterface, name)
+}
+
+func (cr *CommandRegistry) LocalCommandName(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) (string, bool) {
+	registration, err := cr.localRegistration(clusterID, manufacturer, direction, identifier)
+
+	if err != nil {
+		return "", false
+	}
+
+	return registration.name(), true
+}
+
+func (cr *CommandRegistry) LocalCommandIdentifierByName(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, name string) (CommandIdentifier, bool) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return commandIdentifierByName(cr.localIdentifierToInterface[clusterID][manufacturer][direction], name)
+}
+
+func commandIdentifierByName(commands map[CommandIdentifier]*commandRegistration, name string) (CommandIdentifier, bool) {
+	for identifier, registration := range commands {
+		if registration.name() == name {
+			return identifier, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,149 @@
+package zcl
+
+import (
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"reflect"
+	"sort"
+)
+
+// RegisterAttributes registers the definitions of attributes of a cluster, registering an identical definition again
+// is permitted. An ID or name which is already registered to a different definition is a conflict and is replaced,
+// returns ErrRegistrationConflict or panics depending on the ConflictPolicy. Definitions which conflict with each other
+// are never registered, as there is nothing to replace.
+func (cr *CommandRegistry) RegisterAttributes(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, definitions ...AttributeDefinition) error {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	for i, definition := range definitions {
+		for _, other := range definitions[:i] {
+			if (other.ID == definition.ID || other.Name == definition.Name) && !reflect.DeepEqual(other, definition) {
+				return cr.reject(fmt.Errorf("%w: attribute 0x%04x %s of cluster %d manufacturer %d conflicts with 0x%04x %s in the same registration", ErrRegistrationConflict, uint16(definition.ID), definition.Name, clusterID, manufacturer, uint16(other.ID), other.Name))
+			}
+		}
+	}
+
+	existing := cr.attributes[clusterID][manufacturer]
+
+	var replaced []AttributeID
+
+	for _, definition := range definitions {
+		for _, other := range existing {
+			if (other.ID == definition.ID || other.Name == definition.Name) && !reflect.DeepEqual(other, definition) {
+				if err := cr.conflict(fmt.Errorf("%w: attribute 0x%04x %s of cluster %d manufacturer %d conflicts with 0x%04x %s", ErrRegistrationConflict, uint16(definition.ID), definition.Name, clusterID, manufacturer, uint16(other.ID), other.Name)); err != nil {
+					return err
+				}
+
+				replaced = append(replaced, other.ID)
+			}
+		}
+	}
+
+	for _, attributeID := range replaced {
+		delete(existing, attributeID)
+	}
+
+	if _, found := cr.attributes[clusterID]; !found {
+		cr.attributes[clusterID] = make(map[zigbee.ManufacturerCode]map[AttributeID]AttributeDefinition)
+	}
+
+	if existing == nil {
+		existing = make(map[AttributeID]AttributeDefinition)
+		cr.attributes[clusterID][manufacturer] = existing
+	}
+
+	for _, definition := range definitions {
+		existing[definition.ID] = definition
+	}
+
+	return nil
+}
+
+func (cr *CommandRegistry) MustRegisterAttributes(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, definitions ...AttributeDefinition) {
+	if err := cr.RegisterAttributes(clusterID, manufacturer, definitions...); err != nil {
+		panic(err)
+	}
+}
+
+func (cr *CommandRegistry) Attribute(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, attributeID AttributeID) (AttributeDefinition, bool) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	definition, found := cr.attributes[clusterID][manufacturer][attributeID]
+	return definition, found
+}
+
+func (cr *CommandRegistry) AttributeName(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, attributeID AttributeID) (string, bool) {
+	definition, found := cr.Attribute(clusterID, manufacturer, attributeID)
+	return definition.Name, found
+}
+
+func (cr *CommandRegistry) AttributeIDByName(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, name string) (AttributeID, bool) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	for _, definition := range cr.attributes[clusterID][manufacturer] {
+		if definition.Name == name {
+			return definition.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// QualifiedAttributeName returns the name of the cluster and attribute, such as "TemperatureMeasurement.MeasuredValue",
+// hexadecimal IDs are used for any unknown part.
+func (cr *CommandRegistry) QualifiedAttributeName(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, attributeID AttributeID) string {
+	name, found := cr.AttributeName(clusterID, manufacturer, attributeID)
+
+	if !found {
+		name = fmt.Sprintf("0x%04x", uint16(attributeID))
+	}
+
+	return ClusterShortName(clusterID) + "." + name
+}
+
+func (cr *CommandRegistry) AttributeClusters() []zigbee.ClusterID {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var clusters []zigbee.ClusterID
+
+	for clusterID := range cr.attributes {
+		clusters = append(clusters, clusterID)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i] < clusters[j] })
+
+	return clusters
+}
+
+func (cr *CommandRegistry) AttributeManufacturers(clusterID zigbee.ClusterID) []zigbee.ManufacturerCode {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var manufacturers []zigbee.ManufacturerCode
+
+	for manufacturer := range cr.attributes[clusterID] {
+		manufacturers = append(manufacturers, manufacturer)
+	}
+
+	sort.Slice(manufacturers, func(i, j int) bool { return manufacturers[i] < manufacturers[j] })
+
+	return manufacturers
+}
+
+func (cr *CommandRegistry) Attributes(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode) []AttributeDefinition {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	var definitions []AttributeDefinition
+
+	for _, definition := range cr.attributes[clusterID][manufacturer] {
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].ID < definitions[j].ID })
+
+	return definitions
+}
@@ -0,0 +1,141 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_CommandRegistryAttributes(t *testing.T) {
+	t.Run("registered attributes can be looked up by ID and name", func(t *testing.T) {
+		cr := NewCommandRegistry()
+
+		err := cr.RegisterAttributes(TemperatureMeasurementId, zigbee.NoManufacturer,
+			AttributeDefinition{ID: 0x0000, Name: "MeasuredValue"},
+			AttributeDefinition{ID: 0x0001, Name: "MinMeasuredValue"},
+		)
+		assert.NoError(t, err)
+
+		name, found := cr.AttributeName(TemperatureMeasurementId, zigbee.NoManufacturer, 0x0001)
+		assert.True(t, found)
+		assert.Equal(t, "MinMeasuredValue", name)
+
+		id, found := cr.AttributeIDByName(TemperatureMeasurementId, zigbee.NoManufacturer, "MeasuredValue")
+		assert.True(t, found)
+		assert.Equal(t, AttributeID(0x0000), id)
+
+		_, found = cr.AttributeName(TemperatureMeasurementId, 0x1234, 0x0001)
+		assert.False(t, found)
+
+		assert.Equal(t, []AttributeDefinition{{ID: 0x0000, Name: "MeasuredValue"}, {ID: 0x0001, Name: "MinMeasuredValue"}}, cr.Attributes(TemperatureMeasurementId, zigbee.NoManufacturer))
+		assert.Equal(t, []zigbee.ClusterID{TemperatureMeasurementId}, cr.AttributeClusters())
+		assert.Equal(t, []zigbee.ManufacturerCode{zigbee.NoManufacturer}, cr.AttributeManufacturers(TemperatureMeasurementId))
+	})
+
+	t.Run("qualified attribute names fall back to hexadecimal", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterAttributes(TemperatureMeasurementId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "MeasuredValue"})
+
+		assert.Equal(t, "TemperatureMeasurement.MeasuredValue", cr.QualifiedAttributeName(TemperatureMeasurementId, zigbee.NoManufacturer, 0x0000))
+		assert.Equal(t, "TemperatureMeasurement.0x0010", cr.QualifiedAttributeName(TemperatureMeasurementId, zigbee.NoManufacturer, 0x0010))
+		assert.Equal(t, "0xfc00.0x0000", cr.QualifiedAttributeName(0xfc00, zigbee.NoManufacturer, 0x0000))
+	})
+
+	t.Run("conflicting attribute registrations error", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+		cr.MustRegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"})
+
+		assert.NoError(t, cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"}))
+
+		err := cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "Other"})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		err = cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0001, Name: "OnOff"})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		err = cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff", DataType: TypeBoolean})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+	})
+
+	t.Run("definitions which conflict within a registration error regardless of policy", func(t *testing.T) {
+		cr := NewCommandRegistry()
+
+		err := cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"}, AttributeDefinition{ID: 0x0000, Name: "Other"})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		err = cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"}, AttributeDefinition{ID: 0x0001, Name: "OnOff"})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		assert.Empty(t, cr.Attributes(OnOffId, zigbee.NoManufacturer))
+
+		assert.NoError(t, cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"}, AttributeDefinition{ID: 0x0000, Name: "OnOff"}))
+
+		cr.SetConflictPolicy(ConflictPanic)
+
+		assert.Panics(t, func() {
+			_ = cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0001, Name: "A"}, AttributeDefinition{ID: 0x0001, Name: "B"})
+		})
+	})
+
+	t.Run("conflicting attribute registrations replace existing ones by default", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0000, Name: "OnOff"})
+
+		assert.NoError(t, cr.RegisterAttributes(OnOffId, zigbee.NoManufacturer, AttributeDefinition{ID: 0x0001, Name: "OnOff"}))
+
+		assert.Equal(t, []AttributeDefinition{{ID: 0x0001, Name: "OnOff"}}, cr.Attributes(OnOffId, zigbee.NoManufacturer))
+	})
+}
+
+func Test_CommandRegistryCommandNames(t *testing.T) {
+	t.Run("command names are taken from the type or definition", func(t *testing.T) {
+		type ThisCommand struct{}
+		type OtherCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.MustRegisterGlobal(1, &ThisCommand{})
+		cr.MustRegisterLocalDefinition(OnOffId, zigbee.NoManufacturer, ClientToServer, 2, CommandDefinition{
+			Name: "Named",
+			New:  func() interface{} { return &OtherCommand{} },
+		})
+
+		name, found := cr.GlobalCommandName(1)
+		assert.True(t, found)
+		assert.Equal(t, "ThisCommand", name)
+
+		identifier, found := cr.GlobalCommandIdentifierByName("ThisCommand")
+		assert.True(t, found)
+		assert.Equal(t, CommandIdentifier(1), identifier)
+
+		name, found = cr.LocalCommandName(OnOffId, zigbee.NoManufacturer, ClientToServer, 2)
+		assert.True(t, found)
+		assert.Equal(t, "Named", name)
+
+		identifier, found = cr.LocalCommandIdentifierByName(OnOffId, zigbee.NoManufacturer, ClientToServer, "Named")
+		assert.True(t, found)
+		assert.Equal(t, CommandIdentifier(2), identifier)
+
+		_, found = cr.LocalCommandName(OnOffId, zigbee.NoManufacturer, ServerToClient, 2)
+		assert.False(t, found)
+	})
+}
+
+func Test_ClusterIDByName(t *testing.T) {
+	t.Run("finds clusters ignoring case and punctuation", func(t *testing.T) {
+		for _, name := range []string{"On/Off", "OnOff", "on_off", "ONOFF"} {
+			clusterID, found := ClusterIDByName(name)
+			assert.True(t, found, name)
+			assert.Equal(t, OnOffId, clusterID, name)
+		}
+
+		_, found := ClusterIDByName("Not A Cluster")
+		assert.False(t, found)
+	})
+
+	t.Run("short names are used for qualified names", func(t *testing.T) {
+		assert.Equal(t, "OnOff", ClusterShortName(OnOffId))
+		assert.Equal(t, "TemperatureMeasurement", ClusterShortName(TemperatureMeasurementId))
+	})
+}
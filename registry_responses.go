@@ -0,0 +1,93 @@
+package zcl
+
+import (
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+)
+
+type localCommandKey struct {
+	clusterID    zigbee.ClusterID
+	manufacturer zigbee.ManufacturerCode
+	direction    Direction
+	identifier   CommandIdentifier
+}
+
+// RegisterLocalResponse records that a local command has a specific response, which is sent in the opposite direction
+// even if the Default Response is disabled. Both commands must already be registered, registering the same response
+// again is permitted but a different response is a conflict handled according to the ConflictPolicy.
+func (cr *CommandRegistry) RegisterLocalResponse(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, request CommandIdentifier, response CommandIdentifier) error {
+	if _, err := cr.localRegistration(clusterID, manufacturer, direction, request); err != nil {
+		return err
+	}
+
+	responseDirection := ClientToServer
+
+	if direction == ClientToServer {
+		responseDirection = ServerToClient
+	}
+
+	if _, err := cr.localRegistration(clusterID, manufacturer, responseDirection, response); err != nil {
+		return err
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	requestKey := localCommandKey{clusterID: clusterID, manufacturer: manufacturer, direction: direction, identifier: request}
+
+	if existing, found := cr.localResponses[requestKey]; found && existing != response {
+		if err := cr.conflict(fmt.Errorf("%w: local command %d of cluster %d manufacturer %d direction %d already has response %d, can not register %d", ErrRegistrationConflict, request, clusterID, manufacturer, direction, existing, response)); err != nil {
+			return err
+		}
+
+		delete(cr.localResponseCommands, localCommandKey{clusterID: clusterID, manufacturer: manufacturer, direction: responseDirection, identifier: existing})
+	}
+
+	cr.localResponses[requestKey] = response
+	cr.localResponseCommands[localCommandKey{clusterID: clusterID, manufacturer: manufacturer, direction: responseDirection, identifier: response}] = true
+
+	return nil
+}
+
+func (cr *CommandRegistry) MustRegisterLocalResponse(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, request CommandIdentifier, response CommandIdentifier) {
+	if err := cr.RegisterLocalResponse(clusterID, manufacturer, direction, request, response); err != nil {
+		panic(err)
+	}
+}
+
+// removeLocalResponses removes any response registered for, or as, the local command.
+func (cr *CommandRegistry) removeLocalResponses(key localCommandKey) {
+	delete(cr.localResponses, key)
+	delete(cr.localResponseCommands, key)
+}
+
+// LocalCommandResponse returns the identifier of the specific response to a local command, using the manufacturer
+// lookup for the cluster.
+func (cr *CommandRegistry) LocalCommandResponse(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, request CommandIdentifier) (CommandIdentifier, bool) {
+	_, matchedManufacturer, err := cr.findLocalRegistration(clusterID, manufacturer, direction, request)
+
+	if err != nil {
+		return 0, false
+	}
+
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	response, found := cr.localResponses[localCommandKey{clusterID: clusterID, manufacturer: matchedManufacturer, direction: direction, identifier: request}]
+	return response, found
+}
+
+// IsLocalCommandResponse returns true if the local command was registered as the specific response to another, using
+// the manufacturer lookup for the cluster.
+func (cr *CommandRegistry) IsLocalCommandResponse(clusterID zigbee.ClusterID, manufacturer zigbee.ManufacturerCode, direction Direction, identifier CommandIdentifier) bool {
+	_, matchedManufacturer, err := cr.findLocalRegistration(clusterID, manufacturer, direction, identifier)
+
+	if err != nil {
+		return false
+	}
+
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return cr.localResponseCommands[localCommandKey{clusterID: clusterID, manufacturer: matchedManufacturer, direction: direction, identifier: identifier}]
+}
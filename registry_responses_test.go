@@ -0,0 +1,87 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_CommandRegistryResponses(t *testing.T) {
+	type Query struct{}
+	type Answer struct{}
+
+	t.Run("a registered response can be looked up from the request", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00, &Answer{})
+
+		err := cr.RegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00)
+		assert.NoError(t, err)
+
+		response, found := cr.LocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01)
+		assert.True(t, found)
+		assert.Equal(t, CommandIdentifier(0x00), response)
+
+		assert.True(t, cr.IsLocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00))
+		assert.False(t, cr.IsLocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01))
+
+		_, found = cr.LocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00)
+		assert.False(t, found)
+	})
+
+	t.Run("responses are found through the manufacturer fallback", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00, &Answer{})
+		cr.MustRegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00)
+
+		_, found := cr.LocalCommandResponse(IdentifyId, 0x1234, ClientToServer, 0x01)
+		assert.False(t, found)
+		assert.False(t, cr.IsLocalCommandResponse(IdentifyId, 0x1234, ServerToClient, 0x00))
+
+		cr.SetManufacturerLookup(ManufacturerFallback)
+
+		_, found = cr.LocalCommandResponse(IdentifyId, 0x1234, ClientToServer, 0x01)
+		assert.True(t, found)
+		assert.True(t, cr.IsLocalCommandResponse(IdentifyId, 0x1234, ServerToClient, 0x00))
+	})
+
+	t.Run("registering a response requires both commands to be registered", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+
+		err := cr.RegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00)
+		assert.Error(t, err)
+	})
+
+	t.Run("registering a different response to a request is a conflict", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00, &Answer{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x02, &struct{ Other bool }{})
+		cr.MustRegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00)
+
+		assert.NoError(t, cr.RegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00))
+
+		err := cr.RegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x02)
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+	})
+
+	t.Run("unregistering a command removes its response", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00, &Answer{})
+		cr.MustRegisterLocalResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, 0x00)
+
+		cr.UnregisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01)
+		cr.UnregisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00)
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01, &Query{})
+		cr.MustRegisterLocal(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00, &Answer{})
+
+		_, found := cr.LocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ClientToServer, 0x01)
+		assert.False(t, found)
+		assert.False(t, cr.IsLocalCommandResponse(IdentifyId, zigbee.NoManufacturer, ServerToClient, 0x00))
+	})
+}
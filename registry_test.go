@@ -1,9 +1,11 @@
 package zcl
 
 import (
+	"errors"
 	"github.com/shimmeringbee/zigbee"
 	"github.com/stretchr/testify/assert"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -78,3 +80,344 @@ func Test_CommandRegistryGlobal(t *testing.T) {
 		assert.Equal(t, expectedType, actualType)
 	})
 }
+
+func Test_CommandRegistryUnregister(t *testing.T) {
+	t.Run("unregistering a global command removes it", func(t *testing.T) {
+		type ThisCommand struct{}
+		identifier := CommandIdentifier(1)
+
+		cr := NewCommandRegistry()
+		cr.RegisterGlobal(identifier, &ThisCommand{})
+		cr.UnregisterGlobal(identifier)
+
+		_, err := cr.GetGlobalCommand(identifier)
+		assert.Error(t, err)
+
+		_, err = cr.GetGlobalCommandIdentifier(&ThisCommand{})
+		assert.Error(t, err)
+	})
+
+	t.Run("unregistering a local command removes it and empty clusters", func(t *testing.T) {
+		type ThisCommand struct{}
+		identifier := CommandIdentifier(1)
+		clusterId := zigbee.ClusterID(0x1020)
+		manufacturer := zigbee.ManufacturerCode(0x3040)
+
+		cr := NewCommandRegistry()
+		cr.RegisterLocal(clusterId, manufacturer, ClientToServer, identifier, &ThisCommand{})
+		cr.UnregisterLocal(clusterId, manufacturer, ClientToServer, identifier)
+
+		_, err := cr.GetLocalCommand(clusterId, manufacturer, ClientToServer, identifier)
+		assert.Error(t, err)
+
+		_, err = cr.GetLocalCommandIdentifier(clusterId, manufacturer, ClientToServer, &ThisCommand{})
+		assert.Error(t, err)
+
+		assert.Empty(t, cr.LocalClusters())
+	})
+
+	t.Run("unregistering a command which is not registered does nothing", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.UnregisterGlobal(1)
+		cr.UnregisterLocal(0x1020, 0x3040, ServerToClient, 1)
+	})
+}
+
+func Test_CommandRegistryEnumeration(t *testing.T) {
+	t.Run("registered commands can be enumerated in order", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+		cr.RegisterGlobal(2, &CommandTwo{})
+		cr.RegisterGlobal(1, &CommandOne{})
+		cr.RegisterLocal(0x0008, 0, ClientToServer, 2, &CommandTwo{})
+		cr.RegisterLocal(0x0008, 0, ClientToServer, 1, &CommandOne{})
+		cr.RegisterLocal(0x0008, 0x1234, ServerToClient, 1, &CommandOne{})
+		cr.RegisterLocal(0x0006, 0, ServerToClient, 1, &CommandOne{})
+
+		expectedCommands := []RegisteredCommand{
+			{Identifier: 1, Name: "CommandOne", Type: reflect.TypeOf(&CommandOne{})},
+			{Identifier: 2, Name: "CommandTwo", Type: reflect.TypeOf(&CommandTwo{})},
+		}
+
+		assert.Equal(t, expectedCommands, withoutDefinitions(cr.GlobalCommands()))
+		assert.Equal(t, []zigbee.ClusterID{0x0006, 0x0008}, cr.LocalClusters())
+		assert.Equal(t, []zigbee.ManufacturerCode{0, 0x1234}, cr.LocalManufacturers(0x0008))
+		assert.Equal(t, []Direction{ClientToServer}, cr.LocalDirections(0x0008, 0))
+		assert.Equal(t, expectedCommands, withoutDefinitions(cr.LocalCommands(0x0008, 0, ClientToServer)))
+		assert.Empty(t, cr.LocalCommands(0x0008, 0, ServerToClient))
+	})
+}
+
+func withoutDefinitions(commands []RegisteredCommand) []RegisteredCommand {
+	for i := range commands {
+		commands[i].Definition = CommandDefinition{}
+	}
+
+	return commands
+}
+
+func Test_CommandRegistryConcurrency(t *testing.T) {
+	t.Run("registration and lookup may occur concurrently", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func(i int) {
+				defer wg.Done()
+				cr.RegisterLocal(zigbee.ClusterID(i), 0, ClientToServer, 1, &ThisCommand{})
+				cr.UnregisterLocal(zigbee.ClusterID(i), 0, ClientToServer, 1)
+			}(i)
+
+			go func(i int) {
+				defer wg.Done()
+				_, _ = cr.GetLocalCommand(zigbee.ClusterID(i), 0, ClientToServer, 1)
+				_ = cr.LocalClusters()
+			}(i)
+		}
+
+		wg.Wait()
+
+		assert.Empty(t, cr.LocalClusters())
+	})
+}
+
+func Test_CommandRegistryConflicts(t *testing.T) {
+	t.Run("registering the same global command twice is permitted", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+
+		assert.NoError(t, cr.RegisterGlobal(1, &ThisCommand{}))
+		assert.NoError(t, cr.RegisterGlobal(1, &ThisCommand{}))
+	})
+
+	t.Run("registering a different global command to an existing identifier errors", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+
+		assert.NoError(t, cr.RegisterGlobal(1, &CommandOne{}))
+
+		err := cr.RegisterGlobal(1, &CommandTwo{})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		cmd, _ := cr.GetGlobalCommand(1)
+		assert.IsType(t, &CommandOne{}, cmd)
+
+		_, err = cr.GetGlobalCommandIdentifier(&CommandTwo{})
+		assert.Error(t, err)
+	})
+
+	t.Run("registering an existing local command to a different identifier errors", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+
+		assert.NoError(t, cr.RegisterLocal(0x0008, 0, ClientToServer, 1, &ThisCommand{}))
+
+		err := cr.RegisterLocal(0x0008, 0, ClientToServer, 2, &ThisCommand{})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		_, err = cr.GetLocalCommand(0x0008, 0, ClientToServer, 2)
+		assert.Error(t, err)
+
+		assert.NoError(t, cr.RegisterLocal(0x0008, 0, ServerToClient, 2, &ThisCommand{}))
+		assert.NoError(t, cr.RegisterLocal(0x0300, 0, ClientToServer, 2, &ThisCommand{}))
+	})
+
+	t.Run("conflicting registration panics if policy requires", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictPanic)
+
+		cr.MustRegisterLocal(0x0008, 0, ClientToServer, 1, &CommandOne{})
+
+		assert.Panics(t, func() {
+			_ = cr.RegisterLocal(0x0008, 0, ClientToServer, 1, &CommandTwo{})
+		})
+	})
+
+	t.Run("conflicting registrations replace existing ones by default", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+
+		assert.NoError(t, cr.RegisterLocal(0x0008, 0, ClientToServer, 1, &CommandOne{}))
+		assert.NoError(t, cr.RegisterLocal(0x0008, 0, ClientToServer, 1, &CommandTwo{}))
+
+		cmd, err := cr.GetLocalCommand(0x0008, 0, ClientToServer, 1)
+		assert.NoError(t, err)
+		assert.IsType(t, &CommandTwo{}, cmd)
+
+		_, err = cr.GetLocalCommandIdentifier(0x0008, 0, ClientToServer, &CommandOne{})
+		assert.Error(t, err)
+
+		assert.NoError(t, cr.RegisterLocal(0x0008, 0, ClientToServer, 2, &CommandTwo{}))
+
+		_, err = cr.GetLocalCommand(0x0008, 0, ClientToServer, 1)
+		assert.Error(t, err)
+
+		identifier, err := cr.GetLocalCommandIdentifier(0x0008, 0, ClientToServer, &CommandTwo{})
+		assert.NoError(t, err)
+		assert.Equal(t, CommandIdentifier(2), identifier)
+	})
+
+	t.Run("must register respects the policy", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+		cr.MustRegisterGlobal(1, &CommandOne{})
+
+		assert.NotPanics(t, func() {
+			cr.MustRegisterGlobal(1, &CommandTwo{})
+		})
+
+		cr.SetConflictPolicy(ConflictReturnError)
+
+		assert.Panics(t, func() {
+			cr.MustRegisterGlobal(1, &CommandOne{})
+		})
+
+		cmd, _ := cr.GetGlobalCommand(1)
+		assert.IsType(t, &CommandTwo{}, cmd)
+	})
+
+	t.Run("registering the same type with a different definition is a conflict", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+		cr.MustRegisterGlobal(1, &ThisCommand{})
+
+		err := cr.RegisterGlobalDefinition(1, CommandDefinition{
+			Name: "Other",
+			New:  func() interface{} { return &ThisCommand{} },
+		})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		err = cr.RegisterGlobalDefinition(1, CommandDefinition{
+			New:      func() interface{} { return &ThisCommand{} },
+			Validate: func(interface{}) error { return nil },
+		})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+
+		name, _ := cr.GlobalCommandName(1)
+		assert.Equal(t, "ThisCommand", name)
+	})
+}
+
+func Test_CommandRegistryManufacturerLookup(t *testing.T) {
+	t.Run("exact lookup does not fall back to no manufacturer", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.MustRegisterLocal(0x0006, zigbee.NoManufacturer, ClientToServer, 1, &ThisCommand{})
+
+		_, _, err := cr.FindLocalCommand(0x0006, 0x1234, ClientToServer, 1)
+		assert.Error(t, err)
+
+		_, _, err = cr.FindLocalCommandIdentifier(0x0006, 0x1234, ClientToServer, &ThisCommand{})
+		assert.Error(t, err)
+	})
+
+	t.Run("fallback lookup prefers the manufacturer registration", func(t *testing.T) {
+		type StandardCommand struct{}
+		type VendorCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetManufacturerLookup(ManufacturerFallback)
+		cr.MustRegisterLocal(0x0006, zigbee.NoManufacturer, ClientToServer, 1, &StandardCommand{})
+		cr.MustRegisterLocal(0x0006, 0x1234, ClientToServer, 1, &VendorCommand{})
+
+		cmd, manufacturer, err := cr.FindLocalCommand(0x0006, 0x1234, ClientToServer, 1)
+		assert.NoError(t, err)
+		assert.IsType(t, &VendorCommand{}, cmd)
+		assert.Equal(t, zigbee.ManufacturerCode(0x1234), manufacturer)
+
+		cmd, manufacturer, err = cr.FindLocalCommand(0x0006, 0x5678, ClientToServer, 1)
+		assert.NoError(t, err)
+		assert.IsType(t, &StandardCommand{}, cmd)
+		assert.Equal(t, zigbee.NoManufacturer, manufacturer)
+
+		identifier, manufacturer, err := cr.FindLocalCommandIdentifier(0x0006, 0x5678, ClientToServer, &StandardCommand{})
+		assert.NoError(t, err)
+		assert.Equal(t, CommandIdentifier(1), identifier)
+		assert.Equal(t, zigbee.NoManufacturer, manufacturer)
+	})
+
+	t.Run("lookup can be configured per cluster", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetClusterManufacturerLookup(0x0006, ManufacturerFallback)
+		cr.MustRegisterLocal(0x0006, zigbee.NoManufacturer, ClientToServer, 1, &ThisCommand{})
+		cr.MustRegisterLocal(0x0008, zigbee.NoManufacturer, ClientToServer, 1, &ThisCommand{})
+
+		_, _, err := cr.FindLocalCommand(0x0006, 0x1234, ClientToServer, 1)
+		assert.NoError(t, err)
+
+		_, _, err = cr.FindLocalCommand(0x0008, 0x1234, ClientToServer, 1)
+		assert.Error(t, err)
+	})
+}
+
+func Test_CommandRegistryDefinitions(t *testing.T) {
+	t.Run("a command registered by definition uses its constructor", func(t *testing.T) {
+		type ThisCommand struct {
+			Value uint8
+		}
+
+		cr := NewCommandRegistry()
+
+		err := cr.RegisterLocalDefinition(0x0006, zigbee.NoManufacturer, ClientToServer, 1, CommandDefinition{
+			New: func() interface{} { return &ThisCommand{Value: 0xaa} },
+		})
+		assert.NoError(t, err)
+
+		cmd, err := cr.GetLocalCommand(0x0006, zigbee.NoManufacturer, ClientToServer, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, &ThisCommand{Value: 0xaa}, cmd)
+
+		identifier, err := cr.GetLocalCommandIdentifier(0x0006, zigbee.NoManufacturer, ClientToServer, &ThisCommand{})
+		assert.NoError(t, err)
+		assert.Equal(t, CommandIdentifier(1), identifier)
+	})
+
+	t.Run("a definition must have a constructor which returns a pointer", func(t *testing.T) {
+		type ThisCommand struct{}
+
+		cr := NewCommandRegistry()
+
+		assert.Error(t, cr.RegisterGlobalDefinition(1, CommandDefinition{}))
+		assert.Error(t, cr.RegisterGlobalDefinition(1, CommandDefinition{
+			New: func() interface{} { return ThisCommand{} },
+		}))
+	})
+
+	t.Run("a definition conflicts with a prototype of a different type", func(t *testing.T) {
+		type CommandOne struct{}
+		type CommandTwo struct{}
+
+		cr := NewCommandRegistry()
+		cr.SetConflictPolicy(ConflictReturnError)
+		cr.MustRegisterGlobal(1, &CommandOne{})
+
+		err := cr.RegisterGlobalDefinition(1, CommandDefinition{
+			New: func() interface{} { return &CommandTwo{} },
+		})
+		assert.True(t, errors.Is(err, ErrRegistrationConflict))
+	})
+}
@@ -0,0 +1,126 @@
+package zcl
+
+import (
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+)
+
+/*
+ * Zigbee Cluster Library status codes, as per 2.6.3 in ZCL Revision 8.
+ */
+
+type Status uint8
+
+const (
+	StatusSuccess                               Status = 0x00
+	StatusFailure                               Status = 0x01
+	StatusNotAuthorized                         Status = 0x7e
+	StatusReservedFieldNotZero                  Status = 0x7f
+	StatusMalformedCommand                      Status = 0x80
+	StatusUnsupportedClusterCommand             Status = 0x81
+	StatusUnsupportedGeneralCommand             Status = 0x82
+	StatusUnsupportedManufacturerClusterCommand Status = 0x83
+	StatusUnsupportedManufacturerGeneralCommand Status = 0x84
+	StatusInvalidField                          Status = 0x85
+	StatusUnsupportedAttribute                  Status = 0x86
+	StatusInvalidValue                          Status = 0x87
+	StatusReadOnly                              Status = 0x88
+	StatusInsufficientSpace                     Status = 0x89
+	StatusDuplicateExists                       Status = 0x8a
+	StatusNotFound                              Status = 0x8b
+	StatusUnreportableAttribute                 Status = 0x8c
+	StatusInvalidDataType                       Status = 0x8d
+	StatusInvalidSelector                       Status = 0x8e
+	StatusWriteOnly                             Status = 0x8f
+	StatusInconsistentStartupState              Status = 0x90
+	StatusDefinedOutOfBand                      Status = 0x91
+	StatusInconsistent                          Status = 0x92
+	StatusActionDenied                          Status = 0x93
+	StatusTimeout                               Status = 0x94
+	StatusAbort                                 Status = 0x95
+	StatusInvalidImage                          Status = 0x96
+	StatusWaitForData                           Status = 0x97
+	StatusNoImageAvailable                      Status = 0x98
+	StatusRequireMoreImage                      Status = 0x99
+	StatusNotificationPending                   Status = 0x9a
+	StatusHardwareFailure                       Status = 0xc0
+	StatusSoftwareFailure                       Status = 0xc1
+	StatusCalibrationError                      Status = 0xc2
+	StatusUnsupportedCluster                    Status = 0xc3
+)
+
+var StatusNames = map[Status]string{
+	StatusSuccess:                               "SUCCESS",
+	StatusFailure:                               "FAILURE",
+	StatusNotAuthorized:                         "NOT_AUTHORIZED",
+	StatusReservedFieldNotZero:                  "RESERVED_FIELD_NOT_ZERO",
+	StatusMalformedCommand:                      "MALFORMED_COMMAND",
+	StatusUnsupportedClusterCommand:             "UNSUP_CLUSTER_COMMAND",
+	StatusUnsupportedGeneralCommand:             "UNSUP_GENERAL_COMMAND",
+	StatusUnsupportedManufacturerClusterCommand: "UNSUP_MANUF_CLUSTER_COMMAND",
+	StatusUnsupportedManufacturerGeneralCommand: "UNSUP_MANUF_GENERAL_COMMAND",
+	StatusInvalidField:                          "INVALID_FIELD",
+	StatusUnsupportedAttribute:                  "UNSUPPORTED_ATTRIBUTE",
+	StatusInvalidValue:                          "INVALID_VALUE",
+	StatusReadOnly:                              "READ_ONLY",
+	StatusInsufficientSpace:                     "INSUFFICIENT_SPACE",
+	StatusDuplicateExists:                       "DUPLICATE_EXISTS",
+	StatusNotFound:                              "NOT_FOUND",
+	StatusUnreportableAttribute:                 "UNREPORTABLE_ATTRIBUTE",
+	StatusInvalidDataType:                       "INVALID_DATA_TYPE",
+	StatusInvalidSelector:                       "INVALID_SELECTOR",
+	StatusWriteOnly:                             "WRITE_ONLY",
+	StatusInconsistentStartupState:              "INCONSISTENT_STARTUP_STATE",
+	StatusDefinedOutOfBand:                      "DEFINED_OUT_OF_BAND",
+	StatusInconsistent:                          "INCONSISTENT",
+	StatusActionDenied:                          "ACTION_DENIED",
+	StatusTimeout:                               "TIMEOUT",
+	StatusAbort:                                 "ABORT",
+	StatusInvalidImage:                          "INVALID_IMAGE",
+	StatusWaitForData:                           "WAIT_FOR_DATA",
+	StatusNoImageAvailable:                      "NO_IMAGE_AVAILABLE",
+	StatusRequireMoreImage:                      "REQUIRE_MORE_IMAGE",
+	StatusNotificationPending:                   "NOTIFICATION_PENDING",
+	StatusHardwareFailure:                       "HARDWARE_FAILURE",
+	StatusSoftwareFailure:                       "SOFTWARE_FAILURE",
+	StatusCalibrationError:                      "CALIBRATION_ERROR",
+	StatusUnsupportedCluster:                    "UNSUPPORTED_CLUSTER",
+}
+
+func (s Status) String() string {
+	if name, found := StatusNames[s]; found {
+		return name
+	}
+
+	return fmt.Sprintf("UNKNOWN_STATUS(0x%02x)", uint8(s))
+}
+
+func (s Status) IsSuccess() bool {
+	return s == StatusSuccess
+}
+
+// StatusError is returned when a remote device responds with a non success status, the attribute is only meaningful
+// if HasAttribute is true.
+type StatusError struct {
+	Status            Status
+	ClusterID         zigbee.ClusterID
+	CommandIdentifier CommandIdentifier
+	AttributeID       AttributeID
+	HasAttribute      bool
+}
+
+func (e *StatusError) Error() string {
+	if e.HasAttribute {
+		return fmt.Sprintf("ZCL status %s received for cluster 0x%04x command 0x%02x attribute 0x%04x", e.Status, uint16(e.ClusterID), uint8(e.CommandIdentifier), uint16(e.AttributeID))
+	}
+
+	return fmt.Sprintf("ZCL status %s received for cluster 0x%04x command 0x%02x", e.Status, uint16(e.ClusterID), uint8(e.CommandIdentifier))
+}
+
+func NewStatusError(status Status, clusterID zigbee.ClusterID, commandIdentifier CommandIdentifier) *StatusError {
+	return &StatusError{Status: status, ClusterID: clusterID, CommandIdentifier: commandIdentifier}
+}
+
+func NewAttributeStatusError(status Status, clusterID zigbee.ClusterID, commandIdentifier CommandIdentifier, attributeID AttributeID) *StatusError {
+	return &StatusError{Status: status, ClusterID: clusterID, CommandIdentifier: commandIdentifier, AttributeID: attributeID, HasAttribute: true}
+}
@@ -0,0 +1,38 @@
+package zcl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatus_String(t *testing.T) {
+	t.Run("known statuses return their ZCL name", func(t *testing.T) {
+		assert.Equal(t, "SUCCESS", StatusSuccess.String())
+		assert.Equal(t, "UNSUPPORTED_ATTRIBUTE", StatusUnsupportedAttribute.String())
+		assert.Equal(t, "READ_ONLY", StatusReadOnly.String())
+		assert.Equal(t, "INSUFFICIENT_SPACE", StatusInsufficientSpace.String())
+	})
+
+	t.Run("unknown statuses return their value", func(t *testing.T) {
+		assert.Equal(t, "UNKNOWN_STATUS(0xfe)", Status(0xfe).String())
+	})
+}
+
+func TestStatusError(t *testing.T) {
+	t.Run("status errors can be extracted from wrapped errors", func(t *testing.T) {
+		err := fmt.Errorf("failed to configure: %w", NewAttributeStatusError(StatusReadOnly, 0x0006, 0x06, 0x0001))
+
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, StatusReadOnly, statusErr.Status)
+		assert.Equal(t, AttributeID(0x0001), statusErr.AttributeID)
+		assert.True(t, statusErr.HasAttribute)
+	})
+
+	t.Run("error messages include the status name", func(t *testing.T) {
+		assert.Equal(t, "ZCL status UNSUP_CLUSTER_COMMAND received for cluster 0x0006 command 0x02", NewStatusError(StatusUnsupportedClusterCommand, 0x0006, 0x02).Error())
+		assert.Equal(t, "ZCL status READ_ONLY received for cluster 0x0006 command 0x02 attribute 0x4001", NewAttributeStatusError(StatusReadOnly, 0x0006, 0x02, 0x4001).Error())
+	})
+}
@@ -8,9 +8,50 @@ import (
 	"github.com/shimmeringbee/zigbee"
 )
 
-func (cr *CommandRegistry) Unmarshal(appMsg zigbee.ApplicationMessage) (Message, error) {
+var ErrTrailingData = errors.New("trailing data after ZCL command")
+
+type unmarshalOptions struct {
+	preserveUnknownCommands bool
+	strictTrailingData      bool
+	preserveTrailingData    bool
+}
+
+type UnmarshalOption func(*unmarshalOptions)
+
+// PreserveUnknownCommands causes commands which are not registered to be returned as an UnknownCommand containing the
+// raw payload, rather than failing to unmarshal.
+func PreserveUnknownCommands() UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.preserveUnknownCommands = true
+	}
+}
+
+// StrictTrailingData causes unmarshalling to fail with ErrTrailingData if any bytes remain after the command has
+// been decoded.
+func StrictTrailingData() UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.strictTrailingData = true
+	}
+}
+
+// PreserveTrailingData causes any bytes remaining after the command has been decoded to be returned in the Message
+// TrailingData field.
+func PreserveTrailingData() UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.preserveTrailingData = true
+	}
+}
+
+func (cr *CommandRegistry) Unmarshal(appMsg zigbee.ApplicationMessage, options ...UnmarshalOption) (Message, error) {
+	opts := unmarshalOptions{}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
 	header := Header{}
-	var command interface{}
+	var registration *commandRegistration
+	var manufacturerFallback bool
 
 	bb := bitbuffer.NewBitBufferFromBytes(appMsg.Data)
 
@@ -20,38 +61,81 @@ func (cr *CommandRegistry) Unmarshal(appMsg zigbee.ApplicationMessage) (Message,
 
 	switch header.Control.FrameType {
 	case FrameGlobal:
-		foundCommand, err := cr.GetGlobalCommand(header.CommandIdentifier)
+		foundRegistration, err := cr.globalRegistration(header.CommandIdentifier)
 
-		if err != nil {
+		if err != nil && !opts.preserveUnknownCommands {
 			return Message{}, fmt.Errorf("unknown ZCL global command identifier received: %d", header.CommandIdentifier)
 		}
 
-		command = foundCommand
+		registration = foundRegistration
 	case FrameLocal:
-		foundCommand, err := cr.GetLocalCommand(appMsg.ClusterID, header.Manufacturer, header.Control.Direction, header.CommandIdentifier)
+		foundRegistration, matchedManufacturer, err := cr.findLocalRegistration(appMsg.ClusterID, header.Manufacturer, header.Control.Direction, header.CommandIdentifier)
 
-		if err != nil {
+		if err != nil && !opts.preserveUnknownCommands {
 			return Message{}, fmt.Errorf("unknown ZCL local command identifier received: %d", header.CommandIdentifier)
 		}
 
-		command = foundCommand
+		registration = foundRegistration
+		manufacturerFallback = matchedManufacturer != header.Manufacturer
 	default:
 		return Message{}, errors.New("unknown frame type encountered")
 	}
 
+	var command interface{} = &UnknownCommand{}
+
+	if registration != nil {
+		command = registration.definition.New()
+	}
+
 	if err := bytecodec.UnmarshalFromBitBuffer(bb, command); err != nil {
 		return Message{}, err
 	}
 
+	if registration != nil {
+		if err := registration.validate(command); err != nil {
+			return Message{}, fmt.Errorf("ZCL command identifier %d failed validation: %w", header.CommandIdentifier, err)
+		}
+	}
+
+	var trailingData []byte
+
+	if remaining := readRemainingBytes(bb); len(remaining) > 0 {
+		if opts.strictTrailingData {
+			return Message{}, fmt.Errorf("%w: %d bytes remaining after command identifier %d", ErrTrailingData, len(remaining), header.CommandIdentifier)
+		}
+
+		if opts.preserveTrailingData {
+			trailingData = remaining
+		}
+	}
+
 	return Message{
-		FrameType:           header.Control.FrameType,
-		Direction:           header.Control.Direction,
-		TransactionSequence: header.TransactionSequence,
-		Manufacturer:        header.Manufacturer,
-		ClusterID:           appMsg.ClusterID,
-		SourceEndpoint:      appMsg.SourceEndpoint,
-		DestinationEndpoint: appMsg.DestinationEndpoint,
-		CommandIdentifier:   header.CommandIdentifier,
-		Command:             command,
+		FrameType:              header.Control.FrameType,
+		Direction:              header.Control.Direction,
+		DisableDefaultResponse: header.Control.DisableDefaultResponse,
+		ControlReserved:        header.Control.Reserved,
+		TransactionSequence:    header.TransactionSequence,
+		Manufacturer:           header.Manufacturer,
+		ClusterID:              appMsg.ClusterID,
+		SourceEndpoint:         appMsg.SourceEndpoint,
+		DestinationEndpoint:    appMsg.DestinationEndpoint,
+		CommandIdentifier:      header.CommandIdentifier,
+		Command:                command,
+		TrailingData:           trailingData,
+		ManufacturerFallback:   manufacturerFallback,
 	}, nil
 }
+
+func readRemainingBytes(bb *bitbuffer.BitBuffer) []byte {
+	var remaining []byte
+
+	for {
+		b, err := bb.ReadByte()
+
+		if err != nil {
+			return remaining
+		}
+
+		remaining = append(remaining, b)
+	}
+}
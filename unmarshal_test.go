@@ -1,6 +1,7 @@
 package zcl
 
 import (
+	"errors"
 	"github.com/shimmeringbee/zigbee"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -114,4 +115,174 @@ func Test_Unmarshal(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedMessage, actualMessage)
 	})
+	t.Run("disable default response and reserved control bits are unmarshalled", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID:           0x8888,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			Data:                []byte{0b10110000, 0x40, 0xcc, 0xaa},
+		}
+
+		expectedMessage := Message{
+			FrameType:              FrameGlobal,
+			Direction:              ClientToServer,
+			DisableDefaultResponse: true,
+			ControlReserved:        0b101,
+			TransactionSequence:    0x40,
+			Manufacturer:           0x0,
+			ClusterID:              0x8888,
+			SourceEndpoint:         0x03,
+			DestinationEndpoint:    0x04,
+			CommandIdentifier:      commandID,
+			Command: &Command{
+				FieldOne: 0xaa,
+			},
+		}
+
+		actualMessage, err := cr.Unmarshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMessage, actualMessage)
+	})
+	t.Run("unknown local command is preserved when requested", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID:           0xef00,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			Data:                []byte{0b00000101, 0x20, 0x10, 0x40, 0x02, 0x01, 0x02, 0x03},
+		}
+
+		expectedMessage := Message{
+			FrameType:           FrameLocal,
+			Direction:           ClientToServer,
+			TransactionSequence: 0x40,
+			Manufacturer:        0x1020,
+			ClusterID:           0xef00,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			CommandIdentifier:   0x02,
+			Command: &UnknownCommand{
+				Payload: []byte{0x01, 0x02, 0x03},
+			},
+		}
+
+		actualMessage, err := cr.Unmarshal(in, PreserveUnknownCommands())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMessage, actualMessage)
+	})
+
+	t.Run("unknown global command without payload is preserved when requested", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID:           0x0102,
+			SourceEndpoint:      0x03,
+			DestinationEndpoint: 0x04,
+			Data:                []byte{0b00000000, 0x40, 0xfe},
+		}
+
+		actualMessage, err := cr.Unmarshal(in, PreserveUnknownCommands())
+
+		assert.NoError(t, err)
+		assert.Equal(t, CommandIdentifier(0xfe), actualMessage.CommandIdentifier)
+		assert.Empty(t, actualMessage.Command.(*UnknownCommand).Payload)
+	})
+
+	t.Run("trailing data is ignored by default", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID: 0x8888,
+			Data:      []byte{0b00000000, 0x40, 0xcc, 0xaa, 0xbb, 0xcc},
+		}
+
+		actualMessage, err := cr.Unmarshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Command{FieldOne: 0xaa}, actualMessage.Command)
+		assert.Nil(t, actualMessage.TrailingData)
+	})
+
+	t.Run("trailing data results in an error in strict mode", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID: 0x8888,
+			Data:      []byte{0b00000000, 0x40, 0xcc, 0xaa, 0xbb, 0xcc},
+		}
+
+		_, err := cr.Unmarshal(in, StrictTrailingData())
+		assert.True(t, errors.Is(err, ErrTrailingData))
+
+		in.Data = []byte{0b00000000, 0x40, 0xcc, 0xaa}
+
+		_, err = cr.Unmarshal(in, StrictTrailingData())
+		assert.NoError(t, err)
+	})
+
+	t.Run("trailing data is returned on the message when preserved", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID: 0x8888,
+			Data:      []byte{0b00000000, 0x40, 0xcc, 0xaa, 0xbb, 0xcc},
+		}
+
+		actualMessage, err := cr.Unmarshal(in, PreserveTrailingData())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Command{FieldOne: 0xaa}, actualMessage.Command)
+		assert.Equal(t, []byte{0xbb, 0xcc}, actualMessage.TrailingData)
+	})
+
+	t.Run("a partially decoded command results in an error", func(t *testing.T) {
+		in := zigbee.ApplicationMessage{
+			ClusterID: 0x8888,
+			Data:      []byte{0b00000000, 0x40, 0xcc},
+		}
+
+		_, err := cr.Unmarshal(in, PreserveTrailingData())
+		assert.Error(t, err)
+	})
+
+	t.Run("manufacturer specific frame falls back to standard command when permitted", func(t *testing.T) {
+		cr := NewCommandRegistry()
+		cr.SetClusterManufacturerLookup(clusterID, ManufacturerFallback)
+		cr.MustRegisterLocal(clusterID, zigbee.NoManufacturer, ClientToServer, commandID, &Command{})
+
+		in := zigbee.ApplicationMessage{
+			ClusterID: clusterID,
+			Data:      []byte{0b00000101, 0x20, 0x10, 0x40, 0xcc, 0xaa},
+		}
+
+		actualMessage, err := cr.Unmarshal(in)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Command{FieldOne: 0xaa}, actualMessage.Command)
+		assert.Equal(t, zigbee.ManufacturerCode(0x1020), actualMessage.Manufacturer)
+		assert.True(t, actualMessage.ManufacturerFallback)
+	})
+
+	t.Run("a command definition validator is called after unmarshalling", func(t *testing.T) {
+		cr := NewCommandRegistry()
+
+		err := cr.RegisterGlobalDefinition(commandID, CommandDefinition{
+			New: func() interface{} { return &Command{} },
+			Validate: func(command interface{}) error {
+				if command.(*Command).FieldOne > 0x80 {
+					return errors.New("field one out of range")
+				}
+
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+
+		in := zigbee.ApplicationMessage{
+			ClusterID: 0x8888,
+			Data:      []byte{0b00000000, 0x40, 0xcc, 0x10},
+		}
+
+		actualMessage, err := cr.Unmarshal(in)
+		assert.NoError(t, err)
+		assert.Equal(t, &Command{FieldOne: 0x10}, actualMessage.Command)
+
+		in.Data = []byte{0b00000000, 0x40, 0xcc, 0xaa}
+
+		_, err = cr.Unmarshal(in)
+		assert.Error(t, err)
+	})
 }
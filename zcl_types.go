@@ -2,6 +2,7 @@ package zcl
 
 import (
 	"errors"
+	"fmt"
 	"github.com/shimmeringbee/bytecodec"
 	"github.com/shimmeringbee/bytecodec/bitbuffer"
 )
@@ -153,7 +154,87 @@ var DiscreteTypes = map[AttributeDataType]bool{
 	TypeUnknown:        false,
 }
 
+var AttributeDataTypeNames = map[AttributeDataType]string{
+	TypeNull: "nodata",
+
+	TypeData8:  "data8",
+	TypeData16: "data16",
+	TypeData24: "data24",
+	TypeData32: "data32",
+	TypeData40: "data40",
+	TypeData48: "data48",
+	TypeData56: "data56",
+	TypeData64: "data64",
+
+	TypeBoolean: "bool",
+
+	TypeBitmap8:  "map8",
+	TypeBitmap16: "map16",
+	TypeBitmap24: "map24",
+	TypeBitmap32: "map32",
+	TypeBitmap40: "map40",
+	TypeBitmap48: "map48",
+	TypeBitmap56: "map56",
+	TypeBitmap64: "map64",
+
+	TypeUnsignedInt8:  "uint8",
+	TypeUnsignedInt16: "uint16",
+	TypeUnsignedInt24: "uint24",
+	TypeUnsignedInt32: "uint32",
+	TypeUnsignedInt40: "uint40",
+	TypeUnsignedInt48: "uint48",
+	TypeUnsignedInt56: "uint56",
+	TypeUnsignedInt64: "uint64",
+
+	TypeSignedInt8:  "int8",
+	TypeSignedInt16: "int16",
+	TypeSignedInt24: "int24",
+	TypeSignedInt32: "int32",
+	TypeSignedInt40: "int40",
+	TypeSignedInt48: "int48",
+	TypeSignedInt56: "int56",
+	TypeSignedInt64: "int64",
+
+	TypeEnum8:  "enum8",
+	TypeEnum16: "enum16",
+
+	TypeFloatSemi:   "semi",
+	TypeFloatSingle: "single",
+	TypeFloatDouble: "double",
+
+	TypeStringOctet8:      "octstr",
+	TypeStringCharacter8:  "string",
+	TypeStringOctet16:     "octstr16",
+	TypeStringCharacter16: "string16",
+
+	TypeArray:     "array",
+	TypeStructure: "struct",
+	TypeSet:       "set",
+	TypeBag:       "bag",
+
+	TypeTimeOfDay: "ToD",
+	TypeDate:      "date",
+	TypeUTCTime:   "UTC",
+
+	TypeClusterID:   "clusterId",
+	TypeAttributeID: "attribId",
+	TypeBACnetOID:   "bacOID",
+
+	TypeIEEEAddress:    "EUI64",
+	TypeSecurityKey128: "key128",
+	TypeUnknown:        "unk",
+}
+
 type AttributeDataType byte
+
+func (t AttributeDataType) String() string {
+	if name, found := AttributeDataTypeNames[t]; found {
+		return name
+	}
+
+	return fmt.Sprintf("type(0x%02x)", uint8(t))
+}
+
 type AttributeID uint16
 
 type AttributeDataValue struct {
@@ -211,6 +292,9 @@ func (a *AttributeDataValue) Unmarshal(bb *bitbuffer.BitBuffer, ctx bytecodec.Co
 type AttributeDataTypeValue struct {
 	DataType AttributeDataType
 	Value    interface{}
+	// Invalid is set if the value is the data type's non-value, it is only set when unmarshalling booleans, strings
+	// and collections as their non-value has no Go representation, their Value is left as the zero value.
+	Invalid bool
 }
 
 func (a *AttributeDataTypeValue) Marshal(bb *bitbuffer.BitBuffer, ctx bytecodec.Context) error {
@@ -218,6 +302,10 @@ func (a *AttributeDataTypeValue) Marshal(bb *bitbuffer.BitBuffer, ctx bytecodec.
 		return err
 	}
 
+	if a.Invalid {
+		return marshalNonValue(bb, a.DataType)
+	}
+
 	return marshalZCLType(bb, ctx, a.DataType, a.Value)
 }
 
@@ -228,13 +316,14 @@ func (a *AttributeDataTypeValue) Unmarshal(bb *bitbuffer.BitBuffer, ctx bytecode
 		a.DataType = AttributeDataType(dt)
 	}
 
-	val, err := unmarshalZCLType(bb, a.DataType, ctx)
+	val, invalid, err := unmarshalZCLValue(bb, a.DataType, ctx)
 
 	if err != nil {
 		return err
 	}
 
 	a.Value = val
+	a.Invalid = invalid
 
 	return nil
 }
@@ -0,0 +1,414 @@
+package zcl
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownDataTypeName = errors.New("unknown ZCL data type name")
+
+/*
+ * JSON representation of ZCL values, AttributeDataTypeValue is encoded as {"type": "uint8", "value": 1} using the
+ * names in AttributeDataTypeNames. Values are encoded as:
+ *
+ *   integers, enums, bitmaps, IDs  number
+ *   semi, single, double           number, or "NaN", "+Inf" and "-Inf"
+ *   string, string16               string
+ *   data8 - data64, octstr, key128 hexadecimal string
+ *   ToD, date                      object
+ *   struct                         array of {"type", "value"} objects
+ *   array, set, bag                {"type": element type, "values": array of values}
+ *   non-values                     null
+ */
+
+func (t AttributeDataType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *AttributeDataType) UnmarshalText(text []byte) error {
+	name := string(text)
+
+	for dt, dtName := range AttributeDataTypeNames {
+		if dtName == name {
+			*t = dt
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(name, "type(") && strings.HasSuffix(name, ")") {
+		if value, err := strconv.ParseUint(name[5:len(name)-1], 0, 8); err == nil {
+			*t = AttributeDataType(value)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownDataTypeName, name)
+}
+
+type attributeDataTypeValueJSON struct {
+	DataType AttributeDataType `json:"type"`
+	Value    json.RawMessage   `json:"value"`
+}
+
+func (a AttributeDataTypeValue) MarshalJSON() ([]byte, error) {
+	if a.Invalid {
+		a.Value = NonValue{}
+	}
+
+	value, err := valueToJSON(a.DataType, a.Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		DataType AttributeDataType `json:"type"`
+		Value    interface{}       `json:"value"`
+	}{DataType: a.DataType, Value: value})
+}
+
+func (a *AttributeDataTypeValue) UnmarshalJSON(data []byte) error {
+	raw := attributeDataTypeValueJSON{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	value, err := valueFromJSON(raw.DataType, raw.Value)
+
+	if err != nil {
+		return err
+	}
+
+	a.DataType = raw.DataType
+	a.Value = value
+	a.Invalid = false
+
+	if _, isNonValue := value.(NonValue); isNonValue {
+		if zero, found := nonValueZero(raw.DataType); found {
+			a.Value = zero
+			a.Invalid = true
+		}
+	}
+
+	return nil
+}
+
+func (s AttributeSlice) MarshalJSON() ([]byte, error) {
+	values := make([]interface{}, len(s.Values))
+
+	for i, item := range s.Values {
+		value, err := valueToJSON(s.DataType, item)
+
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		values[i] = value
+	}
+
+	return json.Marshal(struct {
+		DataType AttributeDataType `json:"type"`
+		Values   []interface{}     `json:"values"`
+	}{DataType: s.DataType, Values: values})
+}
+
+func (s *AttributeSlice) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		DataType AttributeDataType `json:"type"`
+		Values   []json.RawMessage `json:"values"`
+	}{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	values := []interface{}{}
+
+	for i, item := range raw.Values {
+		value, err := valueFromJSON(raw.DataType, item)
+
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+
+		values = append(values, value)
+	}
+
+	s.DataType = raw.DataType
+	s.Values = values
+
+	return nil
+}
+
+// MarshalJSON encodes the value without its data type, as it is held elsewhere in the command.
+func (a AttributeDataValue) MarshalJSON() ([]byte, error) {
+	switch value := a.Value.(type) {
+	case NonValue:
+		return []byte("null"), nil
+	case []byte:
+		return json.Marshal(hex.EncodeToString(value))
+	case float32:
+		return json.Marshal(floatToJSON(float64(value)))
+	case float64:
+		return json.Marshal(floatToJSON(value))
+	}
+
+	return json.Marshal(a.Value)
+}
+
+// UnmarshalJSON stores the undecoded value as a json.RawMessage, as the data type is held elsewhere in the command. The
+// CommandRegistry resolves these using the preceding AttributeDataType when unmarshalling a message.
+func (a *AttributeDataValue) UnmarshalJSON(data []byte) error {
+	a.Value = json.RawMessage(append([]byte(nil), data...))
+	return nil
+}
+
+// resolveAttributeDataValues walks a decoded command converting AttributeDataValues held as json.RawMessage into the
+// Go type of the AttributeDataType field which precedes them, in the same manner as findPreviousDataType.
+func resolveAttributeDataValues(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return resolveAttributeDataValues(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveAttributeDataValues(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		dataType, foundDataType := TypeUnknown, false
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+
+			if !field.CanInterface() {
+				continue
+			}
+
+			switch value := field.Interface().(type) {
+			case AttributeDataType:
+				dataType, foundDataType = value, true
+			case *AttributeDataValue:
+				if value == nil {
+					continue
+				}
+
+				raw, isRaw := value.Value.(json.RawMessage)
+
+				if !isRaw {
+					continue
+				}
+
+				if !foundDataType {
+					return errors.New("unable to find prior attribute data type to extrapolate type information")
+				}
+
+				if DiscreteTypes[dataType] {
+					value.Value = nil
+					continue
+				}
+
+				decoded, err := valueFromJSON(dataType, raw)
+
+				if err != nil {
+					return err
+				}
+
+				value.Value = decoded
+			default:
+				if err := resolveAttributeDataValues(field); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func floatToJSON(value float64) interface{} {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+
+	return value
+}
+
+func isOctetType(dt AttributeDataType) bool {
+	return dt == TypeStringOctet8 || dt == TypeStringOctet16
+}
+
+func valueToJSON(dt AttributeDataType, v interface{}) (interface{}, error) {
+	value, err := canonicalValue(dt, v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := value.(type) {
+	case NonValue:
+		return nil, nil
+	case []byte:
+		return hex.EncodeToString(value), nil
+	case float32:
+		return floatToJSON(float64(value)), nil
+	case float64:
+		return floatToJSON(value), nil
+	case string:
+		if isOctetType(dt) {
+			return hex.EncodeToString([]byte(value)), nil
+		}
+	case zigbee.NetworkKey:
+		return hex.EncodeToString(value[:]), nil
+	}
+
+	return value, nil
+}
+
+func valueFromJSON(dt AttributeDataType, raw json.RawMessage) (interface{}, error) {
+	if raw == nil || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		if dt == TypeNull {
+			return nil, nil
+		}
+
+		return canonicalValue(dt, NonValue{})
+	}
+
+	var value interface{}
+
+	switch {
+	case dt == TypeNull:
+		return nil, nil
+	case isDataType(dt), isOctetType(dt), dt == TypeSecurityKey128:
+		var encoded string
+
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, err
+		}
+
+		data, err := hex.DecodeString(encoded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case isOctetType(dt):
+			value = string(data)
+		case dt == TypeSecurityKey128:
+			key := zigbee.NetworkKey{}
+
+			if len(data) != len(key) {
+				return nil, fmt.Errorf("%w: %d bytes for %s", ErrValueOutOfRange, len(data), dt)
+			}
+
+			copy(key[:], data)
+			value = key
+		default:
+			value = data
+		}
+	case dt == TypeFloatSemi, dt == TypeFloatSingle, dt == TypeFloatDouble:
+		var special string
+
+		if err := json.Unmarshal(raw, &special); err == nil {
+			switch special {
+			case "NaN":
+				value = math.NaN()
+			case "+Inf":
+				value = math.Inf(1)
+			case "-Inf":
+				value = math.Inf(-1)
+			default:
+				return nil, fmt.Errorf("%w: %q for %s", ErrIncompatibleValue, special, dt)
+			}
+		} else {
+			var number float64
+
+			if err := json.Unmarshal(raw, &number); err != nil {
+				return nil, err
+			}
+
+			value = number
+		}
+	default:
+		if _, isInteger := integerBitSize(dt); isInteger {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.UseNumber()
+
+			var number json.Number
+
+			if err := decoder.Decode(&number); err != nil {
+				return nil, err
+			}
+
+			if isSignedType(dt) {
+				parsed, err := strconv.ParseInt(number.String(), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s for %s", ErrIncompatibleValue, number, dt)
+				}
+
+				value = parsed
+			} else {
+				parsed, err := strconv.ParseUint(number.String(), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s for %s", ErrIncompatibleValue, number, dt)
+				}
+
+				value = parsed
+			}
+
+			break
+		}
+
+		target, err := jsonTarget(dt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(raw, target); err != nil {
+			return nil, err
+		}
+
+		value = reflect.ValueOf(target).Elem().Interface()
+	}
+
+	return canonicalValue(dt, value)
+}
+
+func jsonTarget(dt AttributeDataType) (interface{}, error) {
+	switch dt {
+	case TypeBoolean:
+		return new(bool), nil
+	case TypeStringCharacter8, TypeStringCharacter16:
+		return new(string), nil
+	case TypeTimeOfDay:
+		return new(TimeOfDay), nil
+	case TypeDate:
+		return new(Date), nil
+	case TypeStructure:
+		return &[]AttributeDataTypeValue{}, nil
+	case TypeArray, TypeSet, TypeBag:
+		return new(AttributeSlice), nil
+	}
+
+	return nil, fmt.Errorf("unsupported ZCL type to unmarshal from JSON: %s", dt)
+}
@@ -0,0 +1,125 @@
+package zcl
+
+import (
+	"encoding/json"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_AttributeDataTypeValue_JSON(t *testing.T) {
+	t.Run("round trips values of each type", func(t *testing.T) {
+		values := []AttributeDataTypeValue{
+			{DataType: TypeNull, Value: nil},
+			{DataType: TypeData16, Value: []byte{0x01, 0x02}},
+			{DataType: TypeBoolean, Value: true},
+			{DataType: TypeBitmap24, Value: uint64(0x010203)},
+			{DataType: TypeUnsignedInt64, Value: uint64(math.MaxUint64 - 1)},
+			{DataType: TypeSignedInt32, Value: int64(-200)},
+			{DataType: TypeEnum8, Value: uint8(0x20)},
+			{DataType: TypeFloatSingle, Value: float32(1.5)},
+			{DataType: TypeFloatDouble, Value: math.Inf(-1)},
+			{DataType: TypeStringOctet8, Value: "\x00\xff"},
+			{DataType: TypeStringCharacter16, Value: "hello"},
+			{DataType: TypeTimeOfDay, Value: TimeOfDay{Hours: 1, Minutes: 2, Seconds: 3, Hundredths: 4}},
+			{DataType: TypeDate, Value: Date{Year: 120, Month: 1, DayOfMonth: 2, DayOfWeek: 3}},
+			{DataType: TypeUTCTime, Value: UTCTime(0x11223344)},
+			{DataType: TypeClusterID, Value: zigbee.ClusterID(0x0006)},
+			{DataType: TypeAttributeID, Value: AttributeID(0x0021)},
+			{DataType: TypeIEEEAddress, Value: zigbee.IEEEAddress(0x0102030405060708)},
+			{DataType: TypeSecurityKey128, Value: zigbee.NetworkKey{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}},
+			{DataType: TypeUnsignedInt8, Value: NonValue{}},
+			{DataType: TypeBoolean, Value: false, Invalid: true},
+			{DataType: TypeStringCharacter8, Value: "", Invalid: true},
+		}
+
+		for _, expected := range values {
+			data, err := json.Marshal(expected)
+			assert.NoError(t, err, expected.DataType.String())
+
+			actual := AttributeDataTypeValue{}
+			err = json.Unmarshal(data, &actual)
+			assert.NoError(t, err, expected.DataType.String())
+			assert.Equal(t, expected, actual, expected.DataType.String())
+		}
+	})
+
+	t.Run("encodes NaN as a string", func(t *testing.T) {
+		data, err := json.Marshal(AttributeDataTypeValue{DataType: TypeFloatSingle, Value: float32(math.NaN())})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type":"single","value":"NaN"}`, string(data))
+
+		actual := AttributeDataTypeValue{}
+		err = json.Unmarshal(data, &actual)
+		assert.NoError(t, err)
+		assert.True(t, math.IsNaN(float64(actual.Value.(float32))))
+	})
+
+	t.Run("round trips structures, arrays, sets and bags", func(t *testing.T) {
+		expected := AttributeDataTypeValue{
+			DataType: TypeStructure,
+			Value: []AttributeDataTypeValue{
+				{DataType: TypeUnsignedInt16, Value: uint64(0x1234)},
+				{DataType: TypeArray, Value: AttributeSlice{DataType: TypeIEEEAddress, Values: []interface{}{zigbee.IEEEAddress(1), zigbee.IEEEAddress(2)}}},
+				{DataType: TypeSet, Value: AttributeSlice{DataType: TypeStringCharacter8, Values: []interface{}{"a", "b"}}},
+				{DataType: TypeBag, Value: AttributeSlice{DataType: TypeUnsignedInt8, Values: []interface{}{}}},
+			},
+		}
+
+		data, err := json.Marshal(expected)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type":"struct","value":[{"type":"uint16","value":4660},{"type":"array","value":{"type":"EUI64","values":[1,2]}},{"type":"set","value":{"type":"string","values":["a","b"]}},{"type":"bag","value":{"type":"uint8","values":[]}}]}`, string(data))
+
+		actual := AttributeDataTypeValue{}
+		err = json.Unmarshal(data, &actual)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("errors when value does not fit the type", func(t *testing.T) {
+		actual := AttributeDataTypeValue{}
+
+		err := json.Unmarshal([]byte(`{"type":"uint8","value":256}`), &actual)
+		assert.ErrorIs(t, err, ErrValueOutOfRange)
+
+		err = json.Unmarshal([]byte(`{"type":"uint8","value":"one"}`), &actual)
+		assert.Error(t, err)
+	})
+
+	t.Run("errors on unknown type names", func(t *testing.T) {
+		actual := AttributeDataTypeValue{}
+
+		err := json.Unmarshal([]byte(`{"type":"uint9","value":1}`), &actual)
+		assert.ErrorIs(t, err, ErrUnknownDataTypeName)
+	})
+}
+
+func Test_AttributeDataValue_JSON(t *testing.T) {
+	type Record struct {
+		DataType AttributeDataType
+		Value    *AttributeDataValue
+	}
+
+	t.Run("resolves the value from the preceding data type", func(t *testing.T) {
+		expected := []Record{
+			{DataType: TypeSignedInt16, Value: &AttributeDataValue{Value: int64(-5)}},
+			{DataType: TypeUnsignedInt24, Value: &AttributeDataValue{Value: uint64(0x010203)}},
+			{DataType: TypeFloatSingle, Value: &AttributeDataValue{Value: float32(math.Inf(1))}},
+			{DataType: TypeData8, Value: nil},
+		}
+
+		data, err := json.Marshal(expected)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"DataType":"int16","Value":-5},{"DataType":"uint24","Value":66051},{"DataType":"single","Value":"+Inf"},{"DataType":"data8","Value":null}]`, string(data))
+
+		actual := []Record{}
+		err = json.Unmarshal(data, &actual)
+		assert.NoError(t, err)
+
+		err = resolveAttributeDataValues(reflect.ValueOf(&actual))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
@@ -10,79 +10,83 @@ import (
 )
 
 func marshalZCLType(bb *bitbuffer.BitBuffer, ctx bytecodec.Context, dt AttributeDataType, v interface{}) error {
+	if _, isNonValue := v.(NonValue); isNonValue {
+		return marshalNonValue(bb, dt)
+	}
+
 	switch dt {
 	case TypeNull:
 		return nil
 	case TypeData8:
-		return marshalData(bb, v, 1)
+		return marshalData(bb, dt, v, 1)
 	case TypeData16:
-		return marshalData(bb, v, 2)
+		return marshalData(bb, dt, v, 2)
 	case TypeData24:
-		return marshalData(bb, v, 3)
+		return marshalData(bb, dt, v, 3)
 	case TypeData32:
-		return marshalData(bb, v, 4)
+		return marshalData(bb, dt, v, 4)
 	case TypeData40:
-		return marshalData(bb, v, 5)
+		return marshalData(bb, dt, v, 5)
 	case TypeData48:
-		return marshalData(bb, v, 6)
+		return marshalData(bb, dt, v, 6)
 	case TypeData56:
-		return marshalData(bb, v, 7)
+		return marshalData(bb, dt, v, 7)
 	case TypeData64:
-		return marshalData(bb, v, 8)
+		return marshalData(bb, dt, v, 8)
 	case TypeBoolean:
 		return marshalBoolean(bb, v)
 	case TypeBitmap8:
-		return marshalUint(bb, v, 8)
+		return marshalUint(bb, dt, v, 8)
 	case TypeBitmap16:
-		return marshalUint(bb, v, 16)
+		return marshalUint(bb, dt, v, 16)
 	case TypeBitmap24:
-		return marshalUint(bb, v, 24)
+		return marshalUint(bb, dt, v, 24)
 	case TypeBitmap32:
-		return marshalUint(bb, v, 32)
+		return marshalUint(bb, dt, v, 32)
 	case TypeBitmap40:
-		return marshalUint(bb, v, 40)
+		return marshalUint(bb, dt, v, 40)
 	case TypeBitmap48:
-		return marshalUint(bb, v, 48)
+		return marshalUint(bb, dt, v, 48)
 	case TypeBitmap56:
-		return marshalUint(bb, v, 56)
+		return marshalUint(bb, dt, v, 56)
 	case TypeBitmap64:
-		return marshalUint(bb, v, 64)
+		return marshalUint(bb, dt, v, 64)
 	case TypeUnsignedInt8:
-		return marshalUint(bb, v, 8)
+		return marshalUint(bb, dt, v, 8)
 	case TypeUnsignedInt16:
-		return marshalUint(bb, v, 16)
+		return marshalUint(bb, dt, v, 16)
 	case TypeUnsignedInt24:
-		return marshalUint(bb, v, 24)
+		return marshalUint(bb, dt, v, 24)
 	case TypeUnsignedInt32:
-		return marshalUint(bb, v, 32)
+		return marshalUint(bb, dt, v, 32)
 	case TypeUnsignedInt40:
-		return marshalUint(bb, v, 40)
+		return marshalUint(bb, dt, v, 40)
 	case TypeUnsignedInt48:
-		return marshalUint(bb, v, 48)
+		return marshalUint(bb, dt, v, 48)
 	case TypeUnsignedInt56:
-		return marshalUint(bb, v, 56)
+		return marshalUint(bb, dt, v, 56)
 	case TypeUnsignedInt64:
-		return marshalUint(bb, v, 64)
+		return marshalUint(bb, dt, v, 64)
 	case TypeSignedInt8:
-		return marshalInt(bb, v, 8)
+		return marshalInt(bb, dt, v, 8)
 	case TypeSignedInt16:
-		return marshalInt(bb, v, 16)
+		return marshalInt(bb, dt, v, 16)
 	case TypeSignedInt24:
-		return marshalInt(bb, v, 24)
+		return marshalInt(bb, dt, v, 24)
 	case TypeSignedInt32:
-		return marshalInt(bb, v, 32)
+		return marshalInt(bb, dt, v, 32)
 	case TypeSignedInt40:
-		return marshalInt(bb, v, 40)
+		return marshalInt(bb, dt, v, 40)
 	case TypeSignedInt48:
-		return marshalInt(bb, v, 48)
+		return marshalInt(bb, dt, v, 48)
 	case TypeSignedInt56:
-		return marshalInt(bb, v, 56)
+		return marshalInt(bb, dt, v, 56)
 	case TypeSignedInt64:
-		return marshalInt(bb, v, 64)
+		return marshalInt(bb, dt, v, 64)
 	case TypeEnum8:
-		return marshalUint(bb, v, 8)
+		return marshalUint(bb, dt, v, 8)
 	case TypeEnum16:
-		return marshalUint(bb, v, 16)
+		return marshalUint(bb, dt, v, 16)
 	case TypeStringOctet8:
 		return marshalString(bb, v, 8)
 	case TypeStringOctet16:
@@ -111,6 +115,8 @@ func marshalZCLType(bb *bitbuffer.BitBuffer, ctx bytecodec.Context, dt Attribute
 		return marshalStructure(bb, ctx, v)
 	case TypeArray, TypeSet, TypeBag:
 		return marshalSlice(bb, ctx, v)
+	case TypeFloatSemi:
+		return marshalFloatSemi(bb, v)
 	case TypeFloatSingle:
 		return marshalFloatSingle(bb, v)
 	case TypeFloatDouble:
@@ -120,11 +126,17 @@ func marshalZCLType(bb *bitbuffer.BitBuffer, ctx bytecodec.Context, dt Attribute
 	}
 }
 
-func marshalData(bb *bitbuffer.BitBuffer, v interface{}, size int) error {
+func marshalData(bb *bitbuffer.BitBuffer, dt AttributeDataType, v interface{}, size int) error {
 	data, ok := v.([]byte)
 
 	if !ok {
-		return errors.New("could not cast value")
+		value, err := uintInRange(dt, v, size*8)
+
+		if err != nil {
+			return err
+		}
+
+		data = uint64ToBytes(value, size)
 	}
 
 	if len(data) != size {
@@ -154,38 +166,24 @@ func marshalBoolean(bb *bitbuffer.BitBuffer, v interface{}) error {
 	}
 }
 
-func marshalUint(bb *bitbuffer.BitBuffer, v interface{}, bitsize int) error {
-	switch v := v.(type) {
-	case uint:
-		return bb.WriteUint(uint64(v), bitbuffer.LittleEndian, bitsize)
-	case uint8:
-		return bb.WriteUint(uint64(v), bitbuffer.LittleEndian, bitsize)
-	case uint16:
-		return bb.WriteUint(uint64(v), bitbuffer.LittleEndian, bitsize)
-	case uint32:
-		return bb.WriteUint(uint64(v), bitbuffer.LittleEndian, bitsize)
-	case uint64:
-		return bb.WriteUint(v, bitbuffer.LittleEndian, bitsize)
+func marshalUint(bb *bitbuffer.BitBuffer, dt AttributeDataType, v interface{}, bitsize int) error {
+	value, err := uintInRange(dt, v, bitsize)
+
+	if err != nil {
+		return err
 	}
 
-	return errors.New("marshalling uint to ZCL type received unsupported value")
+	return bb.WriteUint(value, bitbuffer.LittleEndian, bitsize)
 }
 
-func marshalInt(bb *bitbuffer.BitBuffer, v interface{}, bitsize int) error {
-	switch v := v.(type) {
-	case int:
-		return bb.WriteInt(int64(v), bitbuffer.LittleEndian, bitsize)
-	case int8:
-		return bb.WriteInt(int64(v), bitbuffer.LittleEndian, bitsize)
-	case int16:
-		return bb.WriteInt(int64(v), bitbuffer.LittleEndian, bitsize)
-	case int32:
-		return bb.WriteInt(int64(v), bitbuffer.LittleEndian, bitsize)
-	case int64:
-		return bb.WriteInt(v, bitbuffer.LittleEndian, bitsize)
+func marshalInt(bb *bitbuffer.BitBuffer, dt AttributeDataType, v interface{}, bitsize int) error {
+	value, err := intInRange(dt, v, bitsize)
+
+	if err != nil {
+		return err
 	}
 
-	return errors.New("marshalling int to ZCL type received unsupported value")
+	return bb.WriteInt(value, bitbuffer.LittleEndian, bitsize)
 }
 
 func marshalString(bb *bitbuffer.BitBuffer, v interface{}, bitsize int) error {
@@ -239,43 +237,19 @@ func marshalDate(bb *bitbuffer.BitBuffer, v interface{}) error {
 }
 
 func marshalUTCTime(bb *bitbuffer.BitBuffer, v interface{}) error {
-	utcTime, ok := v.(UTCTime)
-
-	if !ok {
-		return errors.New("could not cast value")
-	}
-
-	return bb.WriteUint(uint64(utcTime), bitbuffer.LittleEndian, 32)
+	return marshalUint(bb, TypeUTCTime, v, 32)
 }
 
 func marshalClusterID(bb *bitbuffer.BitBuffer, v interface{}) error {
-	clusterID, ok := v.(zigbee.ClusterID)
-
-	if !ok {
-		return errors.New("could not cast value")
-	}
-
-	return bb.WriteUint(uint64(clusterID), bitbuffer.LittleEndian, 16)
+	return marshalUint(bb, TypeClusterID, v, 16)
 }
 
 func marshalAttributeID(bb *bitbuffer.BitBuffer, v interface{}) error {
-	attributeID, ok := v.(AttributeID)
-
-	if !ok {
-		return errors.New("could not cast value")
-	}
-
-	return bb.WriteUint(uint64(attributeID), bitbuffer.LittleEndian, 16)
+	return marshalUint(bb, TypeAttributeID, v, 16)
 }
 
 func marshalIEEEAddress(bb *bitbuffer.BitBuffer, v interface{}) error {
-	ieeeAddress, ok := v.(zigbee.IEEEAddress)
-
-	if !ok {
-		return errors.New("could not cast value")
-	}
-
-	return bb.WriteUint(uint64(ieeeAddress), bitbuffer.LittleEndian, 64)
+	return marshalUint(bb, TypeIEEEAddress, v, 64)
 }
 
 func marshalSecurityKey(bb *bitbuffer.BitBuffer, v interface{}) error {
@@ -289,13 +263,7 @@ func marshalSecurityKey(bb *bitbuffer.BitBuffer, v interface{}) error {
 }
 
 func marshalBACnetOID(bb *bitbuffer.BitBuffer, v interface{}) error {
-	oid, ok := v.(BACnetOID)
-
-	if !ok {
-		return errors.New("could not cast value")
-	}
-
-	return bb.WriteUint(uint64(oid), bitbuffer.LittleEndian, 32)
+	return marshalUint(bb, TypeBACnetOID, v, 32)
 }
 
 func marshalStructure(bb *bitbuffer.BitBuffer, ctx bytecodec.Context, v interface{}) error {
@@ -342,6 +310,16 @@ func marshalSlice(bb *bitbuffer.BitBuffer, ctx bytecodec.Context, v interface{})
 	return nil
 }
 
+func marshalFloatSemi(bb *bitbuffer.BitBuffer, v interface{}) error {
+	value, ok := v.(float32)
+
+	if !ok {
+		return errors.New("could not cast value")
+	}
+
+	return bb.WriteUint(uint64(float32ToSemi(value)), bitbuffer.LittleEndian, 16)
+}
+
 func marshalFloatSingle(bb *bitbuffer.BitBuffer, v interface{}) error {
 	value, ok := v.(float32)
 
@@ -0,0 +1,198 @@
+package zcl
+
+import (
+	"fmt"
+	"github.com/shimmeringbee/bytecodec"
+	"github.com/shimmeringbee/bytecodec/bitbuffer"
+	"math"
+	"reflect"
+)
+
+/*
+ * Non-values (invalid numbers) for ZCL data types, as per 2.6.2 in ZCL Revision 8. Numeric types decode their non-value
+ * as an ordinary number, booleans, strings and collections have no Go representation so decode as their zero value
+ * with the Invalid flag of the AttributeDataTypeValue set. Items of arrays, sets and bags have no flag, so decode as
+ * NonValue.
+ */
+
+// NonValue may be used as the value of any data type which has a non-value to marshal it intentionally.
+type NonValue struct{}
+
+var nonValueBitSizes = map[AttributeDataType]int{
+	TypeUnsignedInt8:  8,
+	TypeUnsignedInt16: 16,
+	TypeUnsignedInt24: 24,
+	TypeUnsignedInt32: 32,
+	TypeUnsignedInt40: 40,
+	TypeUnsignedInt48: 48,
+	TypeUnsignedInt56: 56,
+	TypeUnsignedInt64: 64,
+
+	TypeSignedInt8:  8,
+	TypeSignedInt16: 16,
+	TypeSignedInt24: 24,
+	TypeSignedInt32: 32,
+	TypeSignedInt40: 40,
+	TypeSignedInt48: 48,
+	TypeSignedInt56: 56,
+	TypeSignedInt64: 64,
+
+	TypeBoolean: 8,
+
+	TypeEnum8:  8,
+	TypeEnum16: 16,
+
+	TypeStringOctet8:      8,
+	TypeStringCharacter8:  8,
+	TypeStringOctet16:     16,
+	TypeStringCharacter16: 16,
+
+	TypeArray:     16,
+	TypeStructure: 16,
+	TypeSet:       16,
+	TypeBag:       16,
+
+	TypeFloatSemi:   16,
+	TypeFloatSingle: 32,
+	TypeFloatDouble: 64,
+
+	TypeTimeOfDay: 32,
+	TypeDate:      32,
+	TypeUTCTime:   32,
+
+	TypeClusterID:   16,
+	TypeAttributeID: 16,
+	TypeBACnetOID:   32,
+
+	TypeIEEEAddress: 64,
+}
+
+// decodedNonValue is returned by unmarshalZCLType for types whose non-value has no Go representation, it holds the
+// zero value used in its place.
+type decodedNonValue struct {
+	zero interface{}
+}
+
+// nonValueZero returns the zero value used in place of the non-value of types which have no Go representation of it.
+func nonValueZero(dt AttributeDataType) (interface{}, bool) {
+	switch dt {
+	case TypeBoolean:
+		return false, true
+	case TypeStringOctet8, TypeStringOctet16, TypeStringCharacter8, TypeStringCharacter16:
+		return "", true
+	case TypeStructure:
+		return []AttributeDataTypeValue{}, true
+	case TypeArray, TypeSet, TypeBag:
+		return AttributeSlice{DataType: TypeUnknown, Values: []interface{}{}}, true
+	}
+
+	return nil, false
+}
+
+// unmarshalZCLValue unmarshals a ZCL type, returning true if it was a non-value with no Go representation.
+func unmarshalZCLValue(bb *bitbuffer.BitBuffer, dt AttributeDataType, ctx bytecodec.Context) (interface{}, bool, error) {
+	value, err := unmarshalZCLType(bb, dt, ctx)
+
+	if nonValue, is := value.(decodedNonValue); is {
+		return nonValue.zero, true, err
+	}
+
+	return value, false, err
+}
+
+func HasNonValue(dt AttributeDataType) bool {
+	_, found := nonValueBitSizes[dt]
+	return found
+}
+
+func NewNonValue(dt AttributeDataType) (AttributeDataTypeValue, error) {
+	if !HasNonValue(dt) {
+		return AttributeDataTypeValue{}, fmt.Errorf("ZCL type has no non-value: %d", dt)
+	}
+
+	return AttributeDataTypeValue{DataType: dt, Value: NonValue{}}, nil
+}
+
+func (a *AttributeDataTypeValue) IsNonValue() bool {
+	return (a.Invalid && HasNonValue(a.DataType)) || IsNonValue(a.DataType, a.Value)
+}
+
+func IsNonValue(dt AttributeDataType, v interface{}) bool {
+	bitSize, found := nonValueBitSizes[dt]
+
+	if !found {
+		return false
+	}
+
+	if _, isNonValue := v.(NonValue); isNonValue {
+		return true
+	}
+
+	switch dt {
+	case TypeSignedInt8, TypeSignedInt16, TypeSignedInt24, TypeSignedInt32, TypeSignedInt40, TypeSignedInt48, TypeSignedInt56, TypeSignedInt64:
+		value := reflect.ValueOf(v)
+
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return value.Int() == -1<<(bitSize-1)
+		}
+	case TypeUnsignedInt8, TypeUnsignedInt16, TypeUnsignedInt24, TypeUnsignedInt32, TypeUnsignedInt40, TypeUnsignedInt48, TypeUnsignedInt56, TypeUnsignedInt64,
+		TypeEnum8, TypeEnum16, TypeUTCTime, TypeClusterID, TypeAttributeID, TypeBACnetOID, TypeIEEEAddress:
+		value := reflect.ValueOf(v)
+
+		switch value.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return value.Uint() == allOnes(bitSize)
+		}
+	case TypeFloatSemi, TypeFloatSingle, TypeFloatDouble:
+		switch value := v.(type) {
+		case float32:
+			return math.IsNaN(float64(value))
+		case float64:
+			return math.IsNaN(value)
+		}
+	case TypeTimeOfDay:
+		if tod, ok := v.(TimeOfDay); ok {
+			return tod == TimeOfDay{Hours: 0xff, Minutes: 0xff, Seconds: 0xff, Hundredths: 0xff}
+		}
+	case TypeDate:
+		if date, ok := v.(Date); ok {
+			return date == Date{Year: 0xff, Month: 0xff, DayOfMonth: 0xff, DayOfWeek: 0xff}
+		}
+	}
+
+	return false
+}
+
+func marshalNonValue(bb *bitbuffer.BitBuffer, dt AttributeDataType) error {
+	bitSize, found := nonValueBitSizes[dt]
+
+	if !found {
+		return fmt.Errorf("ZCL type has no non-value to marshal: %d", dt)
+	}
+
+	switch dt {
+	case TypeSignedInt8, TypeSignedInt16, TypeSignedInt24, TypeSignedInt32, TypeSignedInt40, TypeSignedInt48, TypeSignedInt56, TypeSignedInt64:
+		return bb.WriteInt(-1<<(bitSize-1), bitbuffer.LittleEndian, bitSize)
+	case TypeFloatSemi:
+		return bb.WriteUint(0x7e00, bitbuffer.LittleEndian, bitSize)
+	case TypeFloatSingle:
+		return bb.WriteUint(uint64(math.Float32bits(float32(math.NaN()))), bitbuffer.LittleEndian, bitSize)
+	case TypeFloatDouble:
+		return bb.WriteUint(math.Float64bits(math.NaN()), bitbuffer.LittleEndian, bitSize)
+	case TypeArray, TypeSet, TypeBag:
+		if err := bb.WriteByte(byte(TypeUnknown)); err != nil {
+			return err
+		}
+	}
+
+	return bb.WriteUint(allOnes(bitSize), bitbuffer.LittleEndian, bitSize)
+}
+
+func allOnes(bitSize int) uint64 {
+	if bitSize >= 64 {
+		return math.MaxUint64
+	}
+
+	return (1 << bitSize) - 1
+}
@@ -0,0 +1,162 @@
+package zcl
+
+import (
+	"github.com/shimmeringbee/bytecodec"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func Test_NonValue(t *testing.T) {
+	t.Run("every type with a non-value marshals and unmarshals as a non-value", func(t *testing.T) {
+		for dt := range DiscreteTypes {
+			if !HasNonValue(dt) {
+				continue
+			}
+
+			nonValue, err := NewNonValue(dt)
+			assert.NoError(t, err)
+
+			expectedValue := &nonValue
+
+			actualBytes, err := bytecodec.Marshal(&expectedValue)
+			assert.NoError(t, err, "type 0x%02x", dt)
+
+			actualValue := &AttributeDataTypeValue{}
+			err = bytecodec.Unmarshal(actualBytes, &actualValue)
+			assert.NoError(t, err, "type 0x%02x", dt)
+
+			assert.Equal(t, dt, actualValue.DataType)
+			assert.True(t, actualValue.IsNonValue(), "type 0x%02x", dt)
+		}
+	})
+
+	t.Run("types without a non-value can not be marshalled as one", func(t *testing.T) {
+		for _, dt := range []AttributeDataType{TypeNull, TypeData8, TypeBitmap16, TypeSecurityKey128} {
+			assert.False(t, HasNonValue(dt))
+
+			_, err := NewNonValue(dt)
+			assert.Error(t, err)
+
+			inputValue := &AttributeDataTypeValue{DataType: dt, Value: NonValue{}}
+
+			_, err = bytecodec.Marshal(&inputValue)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("non-values are marshalled with the ZCL invalid number", func(t *testing.T) {
+		testCases := map[AttributeDataType][]byte{
+			TypeUnsignedInt8:      {0x20, 0xff},
+			TypeSignedInt16:       {0x29, 0x00, 0x80},
+			TypeSignedInt24:       {0x2a, 0x00, 0x00, 0x80},
+			TypeBoolean:           {0x10, 0xff},
+			TypeEnum16:            {0x31, 0xff, 0xff},
+			TypeStringOctet8:      {0x41, 0xff},
+			TypeStringCharacter16: {0x44, 0xff, 0xff},
+			TypeStructure:         {0x4c, 0xff, 0xff},
+			TypeArray:             {0x48, 0xff, 0xff, 0xff},
+			TypeFloatSemi:         {0x38, 0x00, 0x7e},
+			TypeUTCTime:           {0xe2, 0xff, 0xff, 0xff, 0xff},
+			TypeDate:              {0xe1, 0xff, 0xff, 0xff, 0xff},
+			TypeClusterID:         {0xe9, 0xff, 0xff},
+		}
+
+		for dt, expectedBytes := range testCases {
+			nonValue, _ := NewNonValue(dt)
+			inputValue := &nonValue
+
+			actualBytes, err := bytecodec.Marshal(&inputValue)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedBytes, actualBytes, "type 0x%02x", dt)
+		}
+	})
+
+	t.Run("numeric non-values are decoded as numbers which report as non-values", func(t *testing.T) {
+		actualValue := &AttributeDataTypeValue{}
+
+		err := bytecodec.Unmarshal([]byte{0x29, 0x00, 0x80}, &actualValue)
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(math.MinInt16), actualValue.Value)
+		assert.True(t, actualValue.IsNonValue())
+	})
+
+	t.Run("non-values without a Go representation are decoded as the zero value and flagged invalid", func(t *testing.T) {
+		testCases := []struct {
+			data     []byte
+			expected interface{}
+		}{
+			{data: []byte{0x10, 0xff}, expected: false},
+			{data: []byte{0x42, 0xff}, expected: ""},
+			{data: []byte{0x41, 0xff}, expected: ""},
+			{data: []byte{0x4c, 0xff, 0xff}, expected: []AttributeDataTypeValue{}},
+			{data: []byte{0x48, 0x20, 0xff, 0xff}, expected: AttributeSlice{DataType: TypeUnsignedInt8, Values: []interface{}{}}},
+		}
+
+		for _, testCase := range testCases {
+			actualValue := &AttributeDataTypeValue{}
+
+			err := bytecodec.Unmarshal(testCase.data, &actualValue)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, actualValue.Value)
+			assert.True(t, actualValue.Invalid)
+			assert.True(t, actualValue.IsNonValue())
+
+			actualBytes, err := bytecodec.Marshal(&actualValue)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.data[:1], actualBytes[:1])
+			assert.Len(t, actualBytes, len(testCase.data))
+		}
+	})
+
+	t.Run("non-value items of collections decode as NonValue and re-encode unchanged", func(t *testing.T) {
+		data := []byte{0x48, 0x10, 0x03, 0x00, 0xff, 0x01, 0xff}
+
+		actualValue := &AttributeDataTypeValue{}
+
+		err := bytecodec.Unmarshal(data, &actualValue)
+		assert.NoError(t, err)
+
+		assert.Equal(t, AttributeSlice{DataType: TypeBoolean, Values: []interface{}{NonValue{}, true, NonValue{}}}, actualValue.Value)
+		assert.False(t, actualValue.Invalid)
+
+		actualBytes, err := bytecodec.Marshal(&actualValue)
+		assert.NoError(t, err)
+		assert.Equal(t, data, actualBytes)
+	})
+
+	t.Run("ordinary booleans and strings are not flagged invalid", func(t *testing.T) {
+		actualValue := &AttributeDataTypeValue{}
+
+		err := bytecodec.Unmarshal([]byte{0x10, 0x00}, &actualValue)
+		assert.NoError(t, err)
+
+		assert.Equal(t, false, actualValue.Value)
+		assert.False(t, actualValue.IsNonValue())
+
+		err = bytecodec.Unmarshal([]byte{0x42, 0x00}, &actualValue)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "", actualValue.Value)
+		assert.False(t, actualValue.IsNonValue())
+	})
+
+	t.Run("ordinary values are not reported as non-values", func(t *testing.T) {
+		assert.False(t, IsNonValue(TypeSignedInt16, int64(-32767)))
+		assert.False(t, IsNonValue(TypeUnsignedInt8, uint64(0xfe)))
+		assert.False(t, IsNonValue(TypeFloatSingle, float32(1.0)))
+		assert.False(t, IsNonValue(TypeStringCharacter8, "ZigBee"))
+		assert.False(t, IsNonValue(TypeTimeOfDay, TimeOfDay{Hours: 0xff, Minutes: 0xff, Seconds: 0xff, Hundredths: 0}))
+		assert.False(t, IsNonValue(TypeBitmap8, uint64(0xff)))
+	})
+
+	t.Run("named types report their non-value", func(t *testing.T) {
+		assert.True(t, IsNonValue(TypeClusterID, zigbee.ClusterID(0xffff)))
+		assert.True(t, IsNonValue(TypeIEEEAddress, zigbee.IEEEAddress(0xffffffffffffffff)))
+		assert.True(t, IsNonValue(TypeUTCTime, UTCTime(0xffffffff)))
+		assert.True(t, IsNonValue(TypeFloatDouble, math.NaN()))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/shimmeringbee/bytecodec"
 	"github.com/shimmeringbee/zigbee"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -901,6 +902,40 @@ func Test_AttributeDataTypeValue(t *testing.T) {
 		assert.Equal(t, expectedValue, actualValue)
 	})
 
+	t.Run("marshaling and unmarshaling of semi precision floating point", func(t *testing.T) {
+		expectedValue := &AttributeDataTypeValue{
+			DataType: TypeFloatSemi,
+			Value:    float32(9.8046875),
+		}
+		actualValue := &AttributeDataTypeValue{}
+		expectedBytes := []byte{0x38, 0xe7, 0x48}
+
+		actualBytes, err := bytecodec.Marshal(&expectedValue)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBytes, actualBytes)
+
+		err = bytecodec.Unmarshal(expectedBytes, &actualValue)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedValue, actualValue)
+	})
+
+	t.Run("marshaling and unmarshaling of semi precision floating point NaN", func(t *testing.T) {
+		inputValue := &AttributeDataTypeValue{
+			DataType: TypeFloatSemi,
+			Value:    float32(math.NaN()),
+		}
+		actualValue := &AttributeDataTypeValue{}
+		expectedBytes := []byte{0x38, 0x00, 0x7e}
+
+		actualBytes, err := bytecodec.Marshal(&inputValue)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBytes, actualBytes)
+
+		err = bytecodec.Unmarshal(expectedBytes, &actualValue)
+		assert.NoError(t, err)
+		assert.True(t, math.IsNaN(float64(actualValue.Value.(float32))))
+	})
+
 	t.Run("marshaling and unmarshaling of single precision floating point", func(t *testing.T) {
 		expectedValue := &AttributeDataTypeValue{
 			DataType: TypeFloatSingle,
@@ -1032,6 +1067,29 @@ func Test_AttributeDataValue(t *testing.T) {
 		assert.Equal(t, expectedValue, actualValue)
 	})
 
+	t.Run("marshaling and unmarshaling of attribute data value with prior semi precision floating point type", func(t *testing.T) {
+		type SUT struct {
+			DataType AttributeDataType
+			Change   *AttributeDataValue
+		}
+
+		expectedValue := SUT{
+			DataType: TypeFloatSemi,
+			Change:   &AttributeDataValue{Value: float32(0.5)},
+		}
+
+		actualValue := SUT{}
+		expectedBytes := []byte{0x38, 0x00, 0x38}
+
+		actualBytes, err := bytecodec.Marshal(&expectedValue)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBytes, actualBytes)
+
+		err = bytecodec.Unmarshal(expectedBytes, &actualValue)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedValue, actualValue)
+	})
+
 	t.Run("marshaling and unmarshaling of attribute data value without prior type errors", func(t *testing.T) {
 		type SUT struct {
 			One      *AttributeDataValue
@@ -1049,3 +1107,67 @@ func Test_AttributeDataValue(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func Test_AttributeDataTypeValue_MarshalIntegerKinds(t *testing.T) {
+	t.Run("marshaling accepts any integer kind for unsigned types", func(t *testing.T) {
+		values := []interface{}{int(0x1234), int16(0x1234), uint(0x1234), uint16(0x1234), zigbee.ClusterID(0x1234)}
+
+		for _, value := range values {
+			inputValue := &AttributeDataTypeValue{DataType: TypeUnsignedInt16, Value: value}
+
+			actualBytes, err := bytecodec.Marshal(&inputValue)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{0x21, 0x34, 0x12}, actualBytes)
+		}
+	})
+
+	t.Run("marshaling accepts unsigned kinds for signed types", func(t *testing.T) {
+		inputValue := &AttributeDataTypeValue{DataType: TypeSignedInt8, Value: uint8(0x7f)}
+
+		actualBytes, err := bytecodec.Marshal(&inputValue)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x28, 0x7f}, actualBytes)
+	})
+
+	t.Run("marshaling accepts integers for data and identifier types", func(t *testing.T) {
+		inputValue := &AttributeDataTypeValue{DataType: TypeData16, Value: 0x1234}
+
+		actualBytes, err := bytecodec.Marshal(&inputValue)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x09, 0x34, 0x12}, actualBytes)
+
+		inputValue = &AttributeDataTypeValue{DataType: TypeClusterID, Value: uint16(0x0006)}
+
+		actualBytes, err = bytecodec.Marshal(&inputValue)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xe9, 0x06, 0x00}, actualBytes)
+	})
+
+	t.Run("marshaling rejects values which overflow the data type", func(t *testing.T) {
+		values := []AttributeDataTypeValue{
+			{DataType: TypeUnsignedInt24, Value: uint64(0x1000000)},
+			{DataType: TypeUnsignedInt8, Value: -1},
+			{DataType: TypeSignedInt8, Value: 128},
+			{DataType: TypeSignedInt16, Value: uint64(math.MaxUint64)},
+			{DataType: TypeEnum8, Value: 0x100},
+			{DataType: TypeBitmap8, Value: 0x100},
+			{DataType: TypeData8, Value: 0x100},
+			{DataType: TypeAttributeID, Value: 0x10000},
+		}
+
+		for _, value := range values {
+			inputValue := &value
+
+			_, err := bytecodec.Marshal(&inputValue)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), value.DataType.String())
+		}
+	})
+
+	t.Run("marshaling rejects non integer values", func(t *testing.T) {
+		inputValue := &AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: "1"}
+
+		_, err := bytecodec.Marshal(&inputValue)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,145 @@
+package zcl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	UTCTimeInvalid UTCTime = 0xffffffff
+	DateWildcard   uint8   = 0xff
+	TimeWildcard   uint8   = 0xff
+)
+
+var UTCTimeEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var ErrTimeWildcard = errors.New("time contains wildcard fields")
+
+func (u UTCTime) Time() (time.Time, error) {
+	if u == UTCTimeInvalid {
+		return time.Time{}, ErrNonValue
+	}
+
+	return UTCTimeEpoch.Add(time.Duration(u) * time.Second), nil
+}
+
+func UTCTimeFromTime(t time.Time) (UTCTime, error) {
+	seconds := t.Unix() - UTCTimeEpoch.Unix()
+
+	if seconds < 0 || seconds >= int64(UTCTimeInvalid) {
+		return UTCTimeInvalid, fmt.Errorf("%w: %s can not be represented as UTC", ErrValueOutOfRange, t)
+	}
+
+	return UTCTime(seconds), nil
+}
+
+func (d Date) HasWildcard() bool {
+	return d.Year == DateWildcard || d.Month == DateWildcard || d.DayOfMonth == DateWildcard || d.DayOfWeek == DateWildcard
+}
+
+// Time returns the date as midnight in the location provided, the day of week is ignored but year, month and day of
+// month must not be wildcards and must be a valid date.
+func (d Date) Time(loc *time.Location) (time.Time, error) {
+	if d.Year == DateWildcard || d.Month == DateWildcard || d.DayOfMonth == DateWildcard {
+		return time.Time{}, ErrTimeWildcard
+	}
+
+	if d.Month < 1 || d.Month > 12 || d.DayOfMonth < 1 || d.DayOfMonth > 31 {
+		return time.Time{}, fmt.Errorf("%w: month %d day %d is not a date", ErrValueOutOfRange, d.Month, d.DayOfMonth)
+	}
+
+	t := time.Date(1900+int(d.Year), time.Month(d.Month), int(d.DayOfMonth), 0, 0, 0, 0, loc)
+
+	if t.Day() != int(d.DayOfMonth) {
+		return time.Time{}, fmt.Errorf("%w: day %d is not in month %d", ErrValueOutOfRange, d.DayOfMonth, d.Month)
+	}
+
+	return t, nil
+}
+
+func DateFromTime(t time.Time) (Date, error) {
+	yearOffset := t.Year() - 1900
+
+	if yearOffset < 0 || yearOffset >= int(DateWildcard) {
+		return Date{}, fmt.Errorf("%w: %d can not be represented as date", ErrValueOutOfRange, t.Year())
+	}
+
+	return Date{
+		Year:       uint8(yearOffset),
+		Month:      uint8(t.Month()),
+		DayOfMonth: uint8(t.Day()),
+		DayOfWeek:  zclDayOfWeek(t.Weekday()),
+	}, nil
+}
+
+// ZCL numbers days of the week from Monday (1) to Sunday (7).
+func zclDayOfWeek(weekday time.Weekday) uint8 {
+	if weekday == time.Sunday {
+		return 7
+	}
+
+	return uint8(weekday)
+}
+
+func (d Date) Matches(t time.Time) bool {
+	return wildcardMatch(d.Year, DateWildcard, t.Year()-1900) &&
+		wildcardMatch(d.Month, DateWildcard, int(t.Month())) &&
+		wildcardMatch(d.DayOfMonth, DateWildcard, t.Day()) &&
+		wildcardMatch(d.DayOfWeek, DateWildcard, int(zclDayOfWeek(t.Weekday())))
+}
+
+func (t TimeOfDay) HasWildcard() bool {
+	return t.Hours == TimeWildcard || t.Minutes == TimeWildcard || t.Seconds == TimeWildcard || t.Hundredths == TimeWildcard
+}
+
+// Duration returns the time of day as a duration since midnight, no field may be a wildcard and each must be within
+// its range.
+func (t TimeOfDay) Duration() (time.Duration, error) {
+	if t.HasWildcard() {
+		return 0, ErrTimeWildcard
+	}
+
+	if t.Hours > 23 || t.Minutes > 59 || t.Seconds > 59 || t.Hundredths > 99 {
+		return 0, fmt.Errorf("%w: %02d:%02d:%02d.%02d is not a time of day", ErrValueOutOfRange, t.Hours, t.Minutes, t.Seconds, t.Hundredths)
+	}
+
+	return time.Duration(t.Hours)*time.Hour +
+		time.Duration(t.Minutes)*time.Minute +
+		time.Duration(t.Seconds)*time.Second +
+		time.Duration(t.Hundredths)*10*time.Millisecond, nil
+}
+
+// TimeOfDayFromDuration converts a duration since midnight into a TimeOfDay, truncating to hundredths of a second.
+func TimeOfDayFromDuration(d time.Duration) (TimeOfDay, error) {
+	if d < 0 || d >= 24*time.Hour {
+		return TimeOfDay{}, fmt.Errorf("%w: %s can not be represented as time of day", ErrValueOutOfRange, d)
+	}
+
+	return TimeOfDay{
+		Hours:      uint8(d / time.Hour),
+		Minutes:    uint8(d % time.Hour / time.Minute),
+		Seconds:    uint8(d % time.Minute / time.Second),
+		Hundredths: uint8(d % time.Second / (10 * time.Millisecond)),
+	}, nil
+}
+
+func TimeOfDayFromTime(t time.Time) TimeOfDay {
+	return TimeOfDay{
+		Hours:      uint8(t.Hour()),
+		Minutes:    uint8(t.Minute()),
+		Seconds:    uint8(t.Second()),
+		Hundredths: uint8(t.Nanosecond() / int(10*time.Millisecond)),
+	}
+}
+
+func (t TimeOfDay) Matches(other time.Time) bool {
+	return wildcardMatch(t.Hours, TimeWildcard, other.Hour()) &&
+		wildcardMatch(t.Minutes, TimeWildcard, other.Minute()) &&
+		wildcardMatch(t.Seconds, TimeWildcard, other.Second()) &&
+		wildcardMatch(t.Hundredths, TimeWildcard, other.Nanosecond()/int(10*time.Millisecond))
+}
+
+func wildcardMatch(field uint8, wildcard uint8, value int) bool {
+	return field == wildcard || int(field) == value
+}
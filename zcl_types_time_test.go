@@ -0,0 +1,155 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_UTCTime(t *testing.T) {
+	t.Run("converts to time relative to the Zigbee epoch", func(t *testing.T) {
+		actual, err := UTCTime(3600).Time()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2000, time.January, 1, 1, 0, 0, 0, time.UTC), actual)
+	})
+
+	t.Run("invalid value returns ErrNonValue", func(t *testing.T) {
+		_, err := UTCTimeInvalid.Time()
+		assert.True(t, errors.Is(err, ErrNonValue))
+	})
+
+	t.Run("converts from time", func(t *testing.T) {
+		loc := time.FixedZone("UTC+1", 3600)
+
+		actual, err := UTCTimeFromTime(time.Date(2000, time.January, 1, 2, 0, 0, 0, loc))
+		assert.NoError(t, err)
+		assert.Equal(t, UTCTime(3600), actual)
+	})
+
+	t.Run("rejects times before the epoch or beyond the range", func(t *testing.T) {
+		_, err := UTCTimeFromTime(time.Date(1999, time.December, 31, 23, 59, 59, 0, time.UTC))
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = UTCTimeFromTime(UTCTimeEpoch.Add(time.Duration(UTCTimeInvalid) * time.Second))
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+}
+
+func Test_Date(t *testing.T) {
+	t.Run("converts from time with day of week", func(t *testing.T) {
+		actual, err := DateFromTime(time.Date(2020, time.June, 14, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, Date{Year: 120, Month: 6, DayOfMonth: 14, DayOfWeek: 7}, actual)
+
+		actual, err = DateFromTime(time.Date(2020, time.June, 15, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(1), actual.DayOfWeek)
+	})
+
+	t.Run("rejects years which can not be represented", func(t *testing.T) {
+		_, err := DateFromTime(time.Date(1899, time.June, 14, 0, 0, 0, 0, time.UTC))
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = DateFromTime(time.Date(2155, time.June, 14, 0, 0, 0, 0, time.UTC))
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("converts to time at midnight in location", func(t *testing.T) {
+		loc := time.FixedZone("UTC-5", -5*3600)
+
+		actual, err := Date{Year: 120, Month: 6, DayOfMonth: 14, DayOfWeek: DateWildcard}.Time(loc)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, time.June, 14, 0, 0, 0, 0, loc), actual)
+	})
+
+	t.Run("wildcards can not be converted to time", func(t *testing.T) {
+		_, err := Date{Year: DateWildcard, Month: 6, DayOfMonth: 14}.Time(time.UTC)
+		assert.True(t, errors.Is(err, ErrTimeWildcard))
+	})
+
+	t.Run("invalid months and days can not be converted to time", func(t *testing.T) {
+		for _, date := range []Date{
+			{Year: 120, Month: 0, DayOfMonth: 14},
+			{Year: 120, Month: 13, DayOfMonth: 14},
+			{Year: 120, Month: 6, DayOfMonth: 0},
+			{Year: 120, Month: 6, DayOfMonth: 32},
+			{Year: 120, Month: 6, DayOfMonth: 31},
+			{Year: 121, Month: 2, DayOfMonth: 29},
+		} {
+			_, err := date.Time(time.UTC)
+			assert.True(t, errors.Is(err, ErrValueOutOfRange), "%+v", date)
+		}
+
+		actual, err := Date{Year: 120, Month: 2, DayOfMonth: 29}.Time(time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), actual)
+	})
+
+	t.Run("matches with wildcards", func(t *testing.T) {
+		sunday := time.Date(2020, time.June, 14, 0, 0, 0, 0, time.UTC)
+
+		assert.True(t, Date{Year: 120, Month: 6, DayOfMonth: 14, DayOfWeek: 7}.Matches(sunday))
+		assert.True(t, Date{Year: DateWildcard, Month: DateWildcard, DayOfMonth: DateWildcard, DayOfWeek: 7}.Matches(sunday))
+		assert.False(t, Date{Year: DateWildcard, Month: DateWildcard, DayOfMonth: DateWildcard, DayOfWeek: 1}.Matches(sunday))
+		assert.False(t, Date{Year: 121, Month: 6, DayOfMonth: 14, DayOfWeek: DateWildcard}.Matches(sunday))
+	})
+}
+
+func Test_TimeOfDay(t *testing.T) {
+	t.Run("converts to and from duration", func(t *testing.T) {
+		tod := TimeOfDay{Hours: 13, Minutes: 14, Seconds: 15, Hundredths: 16}
+		duration := 13*time.Hour + 14*time.Minute + 15*time.Second + 160*time.Millisecond
+
+		actualDuration, err := tod.Duration()
+		assert.NoError(t, err)
+		assert.Equal(t, duration, actualDuration)
+
+		actualTod, err := TimeOfDayFromDuration(duration + 5*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, tod, actualTod)
+	})
+
+	t.Run("rejects durations outside of a day", func(t *testing.T) {
+		_, err := TimeOfDayFromDuration(24 * time.Hour)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = TimeOfDayFromDuration(-time.Second)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("wildcards can not be converted to duration", func(t *testing.T) {
+		_, err := TimeOfDay{Hours: 1, Minutes: 2, Seconds: 3, Hundredths: TimeWildcard}.Duration()
+		assert.True(t, errors.Is(err, ErrTimeWildcard))
+	})
+
+	t.Run("out of range fields can not be converted to duration", func(t *testing.T) {
+		for _, tod := range []TimeOfDay{
+			{Hours: 24, Minutes: 0, Seconds: 0, Hundredths: 0},
+			{Hours: 25, Minutes: 61, Seconds: 0, Hundredths: 0},
+			{Hours: 12, Minutes: 60, Seconds: 0, Hundredths: 0},
+			{Hours: 12, Minutes: 0, Seconds: 60, Hundredths: 0},
+			{Hours: 12, Minutes: 0, Seconds: 0, Hundredths: 100},
+		} {
+			_, err := tod.Duration()
+			assert.True(t, errors.Is(err, ErrValueOutOfRange), "%+v", tod)
+		}
+
+		actual, err := TimeOfDay{Hours: 23, Minutes: 59, Seconds: 59, Hundredths: 99}.Duration()
+		assert.NoError(t, err)
+		assert.Equal(t, 24*time.Hour-10*time.Millisecond, actual)
+	})
+
+	t.Run("converts from time", func(t *testing.T) {
+		actual := TimeOfDayFromTime(time.Date(2020, time.June, 14, 13, 14, 15, 169000000, time.UTC))
+		assert.Equal(t, TimeOfDay{Hours: 13, Minutes: 14, Seconds: 15, Hundredths: 16}, actual)
+	})
+
+	t.Run("matches with wildcards", func(t *testing.T) {
+		now := time.Date(2020, time.June, 14, 13, 14, 15, 0, time.UTC)
+
+		assert.True(t, TimeOfDay{Hours: 13, Minutes: 14, Seconds: 15, Hundredths: 0}.Matches(now))
+		assert.True(t, TimeOfDay{Hours: 13, Minutes: TimeWildcard, Seconds: TimeWildcard, Hundredths: TimeWildcard}.Matches(now))
+		assert.False(t, TimeOfDay{Hours: 12, Minutes: TimeWildcard, Seconds: TimeWildcard, Hundredths: TimeWildcard}.Matches(now))
+	})
+}
@@ -122,6 +122,8 @@ func unmarshalZCLType(bb *bitbuffer.BitBuffer, dt AttributeDataType, ctx bytecod
 		return unmarshalStructure(bb, ctx)
 	case TypeArray, TypeSet, TypeBag:
 		return unmarshalSlice(bb, ctx)
+	case TypeFloatSemi:
+		return unmarshalFloatSemi(bb)
 	case TypeFloatSingle:
 		return unmarshalFloatSingle(bb)
 	case TypeFloatDouble:
@@ -148,6 +150,8 @@ func unmarshalData(bb *bitbuffer.BitBuffer, size int) (interface{}, error) {
 func unmarshalBoolean(bb *bitbuffer.BitBuffer) (interface{}, error) {
 	if data, err := bb.ReadByte(); err != nil {
 		return nil, err
+	} else if data == 0xff {
+		return decodedNonValue{zero: false}, nil
 	} else {
 		return data != 0x00, nil
 	}
@@ -174,11 +178,27 @@ func unmarshalInt(bb *bitbuffer.BitBuffer, bitsize int) (interface{}, error) {
 }
 
 func unmarshalString(bb *bitbuffer.BitBuffer, bitsize int) (interface{}, error) {
-	if data, err := bb.ReadStringLengthPrefixed(bitbuffer.LittleEndian, bitsize); err != nil {
+	length, err := bb.ReadUint(bitbuffer.LittleEndian, bitsize)
+
+	if err != nil {
 		return nil, err
-	} else {
-		return data, nil
 	}
+
+	if length == allOnes(bitsize) {
+		return decodedNonValue{zero: ""}, nil
+	}
+
+	data := make([]byte, length)
+
+	for i := range data {
+		if b, err := bb.ReadByte(); err != nil {
+			return nil, err
+		} else {
+			data[i] = b
+		}
+	}
+
+	return string(data), nil
 }
 
 func unmarshalStringRune(bb *bitbuffer.BitBuffer, bitsize int) (interface{}, error) {
@@ -310,6 +330,10 @@ func unmarshalStructure(bb *bitbuffer.BitBuffer, ctx bytecodec.Context) (interfa
 		return nil, err
 	}
 
+	if itemCount == allOnes(16) {
+		return decodedNonValue{zero: []AttributeDataTypeValue{}}, nil
+	}
+
 	values := []AttributeDataTypeValue{}
 
 	for i := 0; i < int(itemCount); i++ {
@@ -340,6 +364,10 @@ func unmarshalSlice(bb *bitbuffer.BitBuffer, ctx bytecodec.Context) (interface{}
 
 	itemType := AttributeDataType(rawType)
 
+	if itemCount == allOnes(16) {
+		return decodedNonValue{zero: AttributeSlice{DataType: itemType, Values: []interface{}{}}}, nil
+	}
+
 	value := AttributeSlice{
 		DataType: itemType,
 		Values:   []interface{}{},
@@ -348,6 +376,8 @@ func unmarshalSlice(bb *bitbuffer.BitBuffer, ctx bytecodec.Context) (interface{}
 	for i := 0; i < int(itemCount); i++ {
 		if val, err := unmarshalZCLType(bb, itemType, ctx); err != nil {
 			return nil, err
+		} else if _, isNonValue := val.(decodedNonValue); isNonValue {
+			value.Values = append(value.Values, NonValue{})
 		} else {
 			value.Values = append(value.Values, val)
 		}
@@ -356,6 +386,14 @@ func unmarshalSlice(bb *bitbuffer.BitBuffer, ctx bytecodec.Context) (interface{}
 	return value, nil
 }
 
+func unmarshalFloatSemi(bb *bitbuffer.BitBuffer) (interface{}, error) {
+	if bits, err := bb.ReadUint(bitbuffer.LittleEndian, 16); err != nil {
+		return nil, err
+	} else {
+		return semiToFloat32(uint16(bits)), nil
+	}
+}
+
 func unmarshalFloatSingle(bb *bitbuffer.BitBuffer) (interface{}, error) {
 	if bits, err := bb.ReadUint(bitbuffer.LittleEndian, 32); err != nil {
 		return nil, err
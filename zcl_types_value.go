@@ -0,0 +1,412 @@
+package zcl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shimmeringbee/zigbee"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	ErrNonValue          = errors.New("attribute value is the ZCL non-value")
+	ErrIncompatibleValue = errors.New("value is not compatible with ZCL type")
+	ErrValueOutOfRange   = errors.New("value out of range for ZCL type")
+)
+
+/*
+ * Canonical Go types for each ZCL data type, these are the types produced by unmarshalling and by
+ * NewAttributeDataTypeValue:
+ *
+ *   data8 - data64               []byte
+ *   bool                         bool
+ *   map8 - map64, uint8 - uint64 uint64
+ *   int8 - int64                 int64
+ *   enum8, enum16                uint8, uint16
+ *   semi, single, double         float32, float32, float64
+ *   octstr, string, ...16        string
+ *   struct                       []AttributeDataTypeValue
+ *   array, set, bag              AttributeSlice
+ *   ToD, date, UTC               TimeOfDay, Date, UTCTime
+ *   clusterId, attribId, bacOID  zigbee.ClusterID, AttributeID, BACnetOID
+ *   EUI64, key128                zigbee.IEEEAddress, zigbee.NetworkKey
+ */
+
+func integerBitSize(dt AttributeDataType) (int, bool) {
+	switch {
+	case dt >= TypeData8 && dt <= TypeData64:
+		return int(dt-TypeData8+1) * 8, true
+	case dt >= TypeBitmap8 && dt <= TypeBitmap64:
+		return int(dt-TypeBitmap8+1) * 8, true
+	case dt >= TypeUnsignedInt8 && dt <= TypeUnsignedInt64:
+		return int(dt-TypeUnsignedInt8+1) * 8, true
+	case dt >= TypeSignedInt8 && dt <= TypeSignedInt64:
+		return int(dt-TypeSignedInt8+1) * 8, true
+	case dt == TypeEnum8:
+		return 8, true
+	case dt == TypeEnum16, dt == TypeClusterID, dt == TypeAttributeID:
+		return 16, true
+	case dt == TypeUTCTime, dt == TypeBACnetOID:
+		return 32, true
+	case dt == TypeIEEEAddress:
+		return 64, true
+	}
+
+	return 0, false
+}
+
+func isSignedType(dt AttributeDataType) bool {
+	return dt >= TypeSignedInt8 && dt <= TypeSignedInt64
+}
+
+func isDataType(dt AttributeDataType) bool {
+	return dt >= TypeData8 && dt <= TypeData64
+}
+
+func outOfRange(dt AttributeDataType, v interface{}) error {
+	return fmt.Errorf("%w: %v (%T) for %s", ErrValueOutOfRange, v, v, dt)
+}
+
+func incompatible(dt AttributeDataType, v interface{}) error {
+	return fmt.Errorf("%w: %T for %s", ErrIncompatibleValue, v, dt)
+}
+
+func toUint64(dt AttributeDataType, v interface{}) (uint64, error) {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return 0, outOfRange(dt, v)
+		}
+
+		return uint64(value.Int()), nil
+	}
+
+	return 0, incompatible(dt, v)
+}
+
+func toInt64(dt AttributeDataType, v interface{}) (int64, error) {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return 0, outOfRange(dt, v)
+		}
+
+		return int64(value.Uint()), nil
+	}
+
+	return 0, incompatible(dt, v)
+}
+
+func uintInRange(dt AttributeDataType, v interface{}, bitSize int) (uint64, error) {
+	value, err := toUint64(dt, v)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if value > allOnes(bitSize) {
+		return 0, outOfRange(dt, v)
+	}
+
+	return value, nil
+}
+
+func intInRange(dt AttributeDataType, v interface{}, bitSize int) (int64, error) {
+	value, err := toInt64(dt, v)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if bitSize < 64 && (value < -1<<(bitSize-1) || value > 1<<(bitSize-1)-1) {
+		return 0, outOfRange(dt, v)
+	}
+
+	return value, nil
+}
+
+func toFloat64(dt AttributeDataType, v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	}
+
+	if value, err := toInt64(dt, v); err == nil {
+		return float64(value), nil
+	}
+
+	if value, err := toUint64(dt, v); err == nil {
+		return float64(value), nil
+	}
+
+	return 0, incompatible(dt, v)
+}
+
+func bytesToUint64(data []byte) uint64 {
+	value := uint64(0)
+
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+
+	return value
+}
+
+func uint64ToBytes(value uint64, size int) []byte {
+	data := make([]byte, size)
+
+	for i := size - 1; i >= 0; i-- {
+		data[i] = byte(value)
+		value >>= 8
+	}
+
+	return data
+}
+
+// NewAttributeDataTypeValue constructs an AttributeDataTypeValue holding v converted to the canonical Go type for the
+// data type, any compatible Go type is accepted and an error is returned if v does not fit within the data type.
+func NewAttributeDataTypeValue(dt AttributeDataType, v interface{}) (AttributeDataTypeValue, error) {
+	value, err := canonicalValue(dt, v)
+
+	if err != nil {
+		return AttributeDataTypeValue{}, err
+	}
+
+	return AttributeDataTypeValue{DataType: dt, Value: value}, nil
+}
+
+func canonicalValue(dt AttributeDataType, v interface{}) (interface{}, error) {
+	if _, isNonValue := v.(NonValue); isNonValue {
+		if !HasNonValue(dt) {
+			return nil, incompatible(dt, v)
+		}
+
+		return v, nil
+	}
+
+	bitSize, isInteger := integerBitSize(dt)
+
+	switch {
+	case dt == TypeNull:
+		return nil, nil
+	case isDataType(dt):
+		if data, ok := v.([]byte); ok {
+			if len(data) != bitSize/8 {
+				return nil, outOfRange(dt, v)
+			}
+
+			return data, nil
+		}
+
+		value, err := uintInRange(dt, v, bitSize)
+		if err != nil {
+			return nil, err
+		}
+
+		return uint64ToBytes(value, bitSize/8), nil
+	case isSignedType(dt):
+		return intInRange(dt, v, bitSize)
+	case isInteger:
+		value, err := uintInRange(dt, v, bitSize)
+		if err != nil {
+			return nil, err
+		}
+
+		switch dt {
+		case TypeEnum8:
+			return uint8(value), nil
+		case TypeEnum16:
+			return uint16(value), nil
+		case TypeClusterID:
+			return zigbee.ClusterID(value), nil
+		case TypeAttributeID:
+			return AttributeID(value), nil
+		case TypeUTCTime:
+			return UTCTime(value), nil
+		case TypeBACnetOID:
+			return BACnetOID(value), nil
+		case TypeIEEEAddress:
+			return zigbee.IEEEAddress(value), nil
+		default:
+			return value, nil
+		}
+	}
+
+	switch dt {
+	case TypeBoolean:
+		if value, ok := v.(bool); ok {
+			return value, nil
+		}
+	case TypeFloatSemi, TypeFloatSingle:
+		value, err := toFloat64(dt, v)
+		if err != nil {
+			return nil, err
+		}
+
+		if !math.IsInf(value, 0) && !math.IsNaN(value) && math.Abs(value) > math.MaxFloat32 {
+			return nil, outOfRange(dt, v)
+		}
+
+		return float32(value), nil
+	case TypeFloatDouble:
+		return toFloat64(dt, v)
+	case TypeStringOctet8, TypeStringCharacter8, TypeStringOctet16, TypeStringCharacter16:
+		var value string
+
+		switch v := v.(type) {
+		case string:
+			value = v
+		case []byte:
+			value = string(v)
+		default:
+			return nil, incompatible(dt, v)
+		}
+
+		maxLength := 0xfe
+		if dt == TypeStringOctet16 || dt == TypeStringCharacter16 {
+			maxLength = 0xfffe
+		}
+
+		if len(value) > maxLength {
+			return nil, outOfRange(dt, v)
+		}
+
+		return value, nil
+	case TypeTimeOfDay:
+		if value, ok := v.(TimeOfDay); ok {
+			return value, nil
+		}
+	case TypeDate:
+		if value, ok := v.(Date); ok {
+			return value, nil
+		}
+	case TypeSecurityKey128:
+		if value, ok := v.(zigbee.NetworkKey); ok {
+			return value, nil
+		}
+	case TypeStructure:
+		if value, ok := v.([]AttributeDataTypeValue); ok {
+			members := make([]AttributeDataTypeValue, len(value))
+
+			for i, member := range value {
+				members[i] = member
+
+				if member.Invalid {
+					continue
+				}
+
+				canonical, err := canonicalValue(member.DataType, member.Value)
+				if err != nil {
+					return nil, fmt.Errorf("member %d: %w", i, err)
+				}
+
+				members[i].Value = canonical
+			}
+
+			return members, nil
+		}
+	case TypeArray, TypeSet, TypeBag:
+		if value, ok := v.(AttributeSlice); ok {
+			items := make([]interface{}, len(value.Values))
+
+			for i, item := range value.Values {
+				canonical, err := canonicalValue(value.DataType, item)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+
+				items[i] = canonical
+			}
+
+			return AttributeSlice{DataType: value.DataType, Values: items}, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported ZCL type: %s", dt)
+	}
+
+	return nil, incompatible(dt, v)
+}
+
+func (a *AttributeDataTypeValue) AsUint() (uint64, error) {
+	if a.IsNonValue() {
+		return 0, ErrNonValue
+	}
+
+	if data, ok := a.Value.([]byte); ok && isDataType(a.DataType) {
+		return bytesToUint64(data), nil
+	}
+
+	return toUint64(a.DataType, a.Value)
+}
+
+func (a *AttributeDataTypeValue) AsInt() (int64, error) {
+	if a.IsNonValue() {
+		return 0, ErrNonValue
+	}
+
+	if data, ok := a.Value.([]byte); ok && isDataType(a.DataType) {
+		return toInt64(a.DataType, bytesToUint64(data))
+	}
+
+	return toInt64(a.DataType, a.Value)
+}
+
+func (a *AttributeDataTypeValue) AsFloat() (float64, error) {
+	if a.IsNonValue() {
+		return 0, ErrNonValue
+	}
+
+	return toFloat64(a.DataType, a.Value)
+}
+
+func (a *AttributeDataTypeValue) AsString() (string, error) {
+	if a.IsNonValue() {
+		return "", ErrNonValue
+	}
+
+	if value, ok := a.Value.(string); ok {
+		return value, nil
+	}
+
+	return "", incompatible(a.DataType, a.Value)
+}
+
+func (a *AttributeDataTypeValue) AsBool() (bool, error) {
+	if a.IsNonValue() {
+		return false, ErrNonValue
+	}
+
+	if value, ok := a.Value.(bool); ok {
+		return value, nil
+	}
+
+	return false, incompatible(a.DataType, a.Value)
+}
+
+// AsTime returns UTC and date values as a time.Time in UTC, dates are returned at midnight and must not contain
+// wildcards.
+func (a *AttributeDataTypeValue) AsTime() (time.Time, error) {
+	if a.IsNonValue() {
+		return time.Time{}, ErrNonValue
+	}
+
+	switch value := a.Value.(type) {
+	case UTCTime:
+		return value.Time()
+	case Date:
+		return value.Time(time.UTC)
+	}
+
+	return time.Time{}, incompatible(a.DataType, a.Value)
+}
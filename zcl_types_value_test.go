@@ -0,0 +1,212 @@
+package zcl
+
+import (
+	"errors"
+	"github.com/shimmeringbee/zigbee"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_NewAttributeDataTypeValue(t *testing.T) {
+	t.Run("coerces integer kinds to canonical types", func(t *testing.T) {
+		value, err := NewAttributeDataTypeValue(TypeUnsignedInt16, 0x1234)
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeDataTypeValue{DataType: TypeUnsignedInt16, Value: uint64(0x1234)}, value)
+
+		value, err = NewAttributeDataTypeValue(TypeSignedInt8, int16(-12))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-12), value.Value)
+
+		value, err = NewAttributeDataTypeValue(TypeEnum8, uint(3))
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(3), value.Value)
+
+		value, err = NewAttributeDataTypeValue(TypeClusterID, 0x0006)
+		assert.NoError(t, err)
+		assert.Equal(t, zigbee.ClusterID(0x0006), value.Value)
+
+		value, err = NewAttributeDataTypeValue(TypeData16, 0x1234)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x12, 0x34}, value.Value)
+	})
+
+	t.Run("rejects values out of range", func(t *testing.T) {
+		_, err := NewAttributeDataTypeValue(TypeUnsignedInt8, 256)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = NewAttributeDataTypeValue(TypeUnsignedInt24, -1)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = NewAttributeDataTypeValue(TypeSignedInt8, 128)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = NewAttributeDataTypeValue(TypeSignedInt8, -129)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = NewAttributeDataTypeValue(TypeFloatSingle, math.MaxFloat64)
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+
+		_, err = NewAttributeDataTypeValue(TypeData16, []byte{0x01})
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("rejects incompatible values", func(t *testing.T) {
+		_, err := NewAttributeDataTypeValue(TypeBoolean, 1)
+		assert.True(t, errors.Is(err, ErrIncompatibleValue))
+
+		_, err = NewAttributeDataTypeValue(TypeUnsignedInt8, "1")
+		assert.True(t, errors.Is(err, ErrIncompatibleValue))
+
+		_, err = NewAttributeDataTypeValue(TypeArray, AttributeSlice{DataType: TypeUnsignedInt8, Values: []interface{}{"a"}})
+		assert.True(t, errors.Is(err, ErrIncompatibleValue))
+	})
+
+	t.Run("accepts floats and strings", func(t *testing.T) {
+		value, err := NewAttributeDataTypeValue(TypeFloatSingle, 1.5)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(1.5), value.Value)
+
+		value, err = NewAttributeDataTypeValue(TypeFloatDouble, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10), value.Value)
+
+		value, err = NewAttributeDataTypeValue(TypeStringCharacter8, []byte("abc"))
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", value.Value)
+
+		_, err = NewAttributeDataTypeValue(TypeStringCharacter8, string(make([]byte, 255)))
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("converts the items of arrays and the members of structures", func(t *testing.T) {
+		input := AttributeSlice{DataType: TypeUnsignedInt16, Values: []interface{}{1, uint8(2)}}
+
+		value, err := NewAttributeDataTypeValue(TypeArray, input)
+		assert.NoError(t, err)
+		assert.Equal(t, AttributeSlice{DataType: TypeUnsignedInt16, Values: []interface{}{uint64(1), uint64(2)}}, value.Value)
+		assert.Equal(t, []interface{}{1, uint8(2)}, input.Values)
+
+		value, err = NewAttributeDataTypeValue(TypeStructure, []AttributeDataTypeValue{
+			{DataType: TypeSignedInt8, Value: 3},
+			{DataType: TypeSet, Value: AttributeSlice{DataType: TypeEnum8, Values: []interface{}{4}}},
+			{DataType: TypeBoolean, Value: false, Invalid: true},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []AttributeDataTypeValue{
+			{DataType: TypeSignedInt8, Value: int64(3)},
+			{DataType: TypeSet, Value: AttributeSlice{DataType: TypeEnum8, Values: []interface{}{uint8(4)}}},
+			{DataType: TypeBoolean, Value: false, Invalid: true},
+		}, value.Value)
+
+		_, err = NewAttributeDataTypeValue(TypeStructure, []AttributeDataTypeValue{{DataType: TypeUnsignedInt8, Value: 256}})
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("accepts non-value only for types which have one", func(t *testing.T) {
+		value, err := NewAttributeDataTypeValue(TypeUnsignedInt8, NonValue{})
+		assert.NoError(t, err)
+		assert.True(t, value.IsNonValue())
+
+		_, err = NewAttributeDataTypeValue(TypeSecurityKey128, NonValue{})
+		assert.Error(t, err)
+	})
+}
+
+func Test_AttributeDataTypeValue_Accessors(t *testing.T) {
+	t.Run("AsUint coerces across unsigned types", func(t *testing.T) {
+		values := []AttributeDataTypeValue{
+			{DataType: TypeUnsignedInt16, Value: uint64(0x1234)},
+			{DataType: TypeEnum16, Value: uint16(0x1234)},
+			{DataType: TypeAttributeID, Value: AttributeID(0x1234)},
+			{DataType: TypeData16, Value: []byte{0x12, 0x34}},
+			{DataType: TypeSignedInt32, Value: int64(0x1234)},
+		}
+
+		for _, value := range values {
+			actual, err := value.AsUint()
+			assert.NoError(t, err, value.DataType.String())
+			assert.Equal(t, uint64(0x1234), actual, value.DataType.String())
+		}
+
+		negative := AttributeDataTypeValue{DataType: TypeSignedInt8, Value: int64(-1)}
+		_, err := negative.AsUint()
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("AsInt coerces from unsigned types", func(t *testing.T) {
+		value := AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint64(200)}
+		actual, err := value.AsInt()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(200), actual)
+
+		value = AttributeDataTypeValue{DataType: TypeUnsignedInt64, Value: uint64(math.MaxUint64 - 1)}
+		_, err = value.AsInt()
+		assert.True(t, errors.Is(err, ErrValueOutOfRange))
+	})
+
+	t.Run("AsFloat coerces from integers", func(t *testing.T) {
+		value := AttributeDataTypeValue{DataType: TypeSignedInt16, Value: int64(-200)}
+		actual, err := value.AsFloat()
+		assert.NoError(t, err)
+		assert.Equal(t, float64(-200), actual)
+
+		value = AttributeDataTypeValue{DataType: TypeFloatSemi, Value: float32(0.5)}
+		actual, err = value.AsFloat()
+		assert.NoError(t, err)
+		assert.Equal(t, 0.5, actual)
+	})
+
+	t.Run("AsString and AsBool", func(t *testing.T) {
+		value := AttributeDataTypeValue{DataType: TypeStringCharacter8, Value: "hello"}
+		str, err := value.AsString()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", str)
+
+		_, err = value.AsBool()
+		assert.True(t, errors.Is(err, ErrIncompatibleValue))
+
+		value = AttributeDataTypeValue{DataType: TypeBoolean, Value: true}
+		b, err := value.AsBool()
+		assert.NoError(t, err)
+		assert.True(t, b)
+	})
+
+	t.Run("AsTime converts UTC and dates", func(t *testing.T) {
+		value := AttributeDataTypeValue{DataType: TypeUTCTime, Value: UTCTime(86400)}
+		actual, err := value.AsTime()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC), actual)
+
+		value = AttributeDataTypeValue{DataType: TypeDate, Value: Date{Year: 120, Month: 6, DayOfMonth: 15, DayOfWeek: 1}}
+		actual, err = value.AsTime()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, time.June, 15, 0, 0, 0, 0, time.UTC), actual)
+
+		value = AttributeDataTypeValue{DataType: TypeDate, Value: Date{Year: 0xff, Month: 6, DayOfMonth: 15, DayOfWeek: 1}}
+		_, err = value.AsTime()
+		assert.Error(t, err)
+	})
+
+	t.Run("accessors return ErrNonValue for non-values", func(t *testing.T) {
+		value := AttributeDataTypeValue{DataType: TypeUnsignedInt8, Value: uint64(0xff)}
+		_, err := value.AsUint()
+		assert.True(t, errors.Is(err, ErrNonValue))
+
+		value = AttributeDataTypeValue{DataType: TypeStringCharacter8, Value: NonValue{}}
+		_, err = value.AsString()
+		assert.True(t, errors.Is(err, ErrNonValue))
+	})
+}
+
+func Test_AttributeDataType_String(t *testing.T) {
+	t.Run("returns ZCL short name", func(t *testing.T) {
+		assert.Equal(t, "uint8", TypeUnsignedInt8.String())
+		assert.Equal(t, "string", TypeStringCharacter8.String())
+	})
+
+	t.Run("returns hex for unknown types", func(t *testing.T) {
+		assert.Equal(t, "type(0x05)", AttributeDataType(0x05).String())
+	})
+}